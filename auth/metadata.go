@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// identityKey, roleKey and hotelsKey are the gRPC metadata keys of the
+	// authenticated user.
+	identityKey = "x-user"
+	roleKey     = "x-user-role"
+	hotelsKey   = "x-user-hotels"
+	// clientIPKey is the gRPC metadata key of the address of the client the
+	// frontend serves.
+	clientIPKey = "x-client-ip"
+)
+
+// The roles of users.
+const (
+	RoleGuest        = "guest"
+	RoleHotelManager = "hotel_manager"
+	RoleAdmin        = "admin"
+)
+
+// ValidRole reports whether role is one of the roles of users.
+func ValidRole(role string) bool {
+	switch role {
+	case RoleGuest, RoleHotelManager, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Identity is the user a call is made on behalf of. The zero Identity is
+// an anonymous caller.
+type Identity struct {
+	Username string
+	Role     string
+	// Hotels are the hotels a hotel manager manages.
+	Hotels []string
+}
+
+// Manages reports whether the user is a manager of hotelId.
+func (id Identity) Manages(hotelId string) bool {
+	if id.Role != RoleHotelManager {
+		return false
+	}
+	for _, h := range id.Hotels {
+		if h == hotelId {
+			return true
+		}
+	}
+	return false
+}
+
+type (
+	identityCtxKey struct{}
+	clientIPCtxKey struct{}
+)
+
+// WithIdentity returns a context for calls made on behalf of id.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityCtxKey{}, id)
+}
+
+// FromContext returns the user a call is made on behalf of: the one set
+// with WithIdentity, or else the one received in the gRPC metadata of the
+// incoming call. Users without a role are guests.
+//
+// The metadata is trusted as is; only the frontend authenticates users and
+// backend services are not reachable from outside the cluster.
+func FromContext(ctx context.Context) Identity {
+	if id, ok := ctx.Value(identityCtxKey{}).(Identity); ok {
+		return id
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := Identity{Username: first(md.Get(identityKey))}
+	if id.Username == "" {
+		return Identity{}
+	}
+	id.Role = first(md.Get(roleKey))
+	if id.Role == "" {
+		id.Role = RoleGuest
+	}
+	id.Hotels = md.Get(hotelsKey)
+	return id
+}
+
+// WithClientIP returns a context for calls made for a client at ip.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPCtxKey{}, ip)
+}
+
+// ClientIP returns the address of the client a call is made for, like
+// FromContext. It returns "" when the call does not come from a client.
+func ClientIP(ctx context.Context) string {
+	if ip, ok := ctx.Value(clientIPCtxKey{}).(string); ok {
+		return ip
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return first(md.Get(clientIPKey))
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// UnaryClientInterceptor sends the identity and client address of the
+// context along with outgoing calls, so they travel on through every
+// service of a request.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id := FromContext(ctx); id.Username != "" {
+			kv := []string{identityKey, id.Username, roleKey, id.Role}
+			for _, h := range id.Hotels {
+				kv = append(kv, hotelsKey, h)
+			}
+			ctx = metadata.AppendToOutgoingContext(ctx, kv...)
+		}
+		if ip := ClientIP(ctx); ip != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, clientIPKey, ip)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,338 @@
+// Package oidc logs users in with an OpenID Connect issuer, using the
+// authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"hotelReservation/auth"
+)
+
+const (
+	// minKeyRefreshInterval limits the key set fetches caused by ID tokens
+	// signed with a key that is not known yet.
+	minKeyRefreshInterval = 10 * time.Second
+	// clockSkew is how far the clocks of the issuer and ours may differ.
+	clockSkew = time.Minute
+)
+
+// Config is an OpenID Connect client registration.
+type Config struct {
+	// Issuer is the issuer URL, as it appears in ID tokens.
+	Issuer   string
+	ClientID string
+	// ClientSecret is empty for public clients, which rely on PKCE alone.
+	ClientSecret string
+	RedirectURL  string
+	// Scopes are requested besides "openid".
+	Scopes []string
+}
+
+// Metadata is the subset of the issuer's discovery document used here.
+type Metadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	ResponseTypesSupported        []string `json:"response_types_supported"`
+	SubjectTypesSupported         []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValues       []string `json:"id_token_signing_alg_values_supported"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+	ScopesSupported               []string `json:"scopes_supported,omitempty"`
+}
+
+// Provider is a discovered issuer.
+type Provider struct {
+	config   Config
+	metadata Metadata
+	client   *http.Client
+	keys     *remoteKeys
+}
+
+// Discover reads the discovery document of the issuer of config.
+func Discover(ctx context.Context, config Config, client *http.Client) (*Provider, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	var md Metadata
+	wellKnown := strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, client, wellKnown, &md); err != nil {
+		return nil, err
+	}
+	if md.Issuer != config.Issuer {
+		return nil, fmt.Errorf("issuer %q does not match the configured %q", md.Issuer, config.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, errors.New("incomplete discovery document")
+	}
+
+	return &Provider{
+		config:   config,
+		metadata: md,
+		client:   client,
+		keys:     &remoteKeys{url: md.JWKSURI, client: client},
+	}, nil
+}
+
+// LazyProvider discovers an issuer on first use and again after a failure,
+// so that services start while the issuer is down.
+type LazyProvider struct {
+	mu       sync.Mutex
+	provider *Provider
+}
+
+// Get returns the provider of the issuer of config, discovering it first if
+// needed.
+func (l *LazyProvider) Get(ctx context.Context, config Config, client *http.Client) (*Provider, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.provider == nil {
+		p, err := Discover(ctx, config, client)
+		if err != nil {
+			return nil, err
+		}
+		l.provider = p
+	}
+	return l.provider, nil
+}
+
+// Flow is the per-login state the client keeps between sending the user to
+// the issuer and getting the code back.
+type Flow struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// NewFlow returns a flow with fresh random values.
+func NewFlow() (Flow, error) {
+	var f Flow
+	for _, v := range []*string{&f.State, &f.Nonce, &f.Verifier} {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return Flow{}, err
+		}
+		*v = b64(b)
+	}
+	return f, nil
+}
+
+// CheckState reports whether state is the state of the flow.
+func (f Flow) CheckState(state string) bool {
+	return f.State != "" && subtle.ConstantTimeCompare([]byte(f.State), []byte(state)) == 1
+}
+
+// AuthCodeURL returns the URL to send the user to for logging in.
+func (p *Provider) AuthCodeURL(f Flow) string {
+	challenge := sha256.Sum256([]byte(f.Verifier))
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(append([]string{"openid"}, p.config.Scopes...), " ")},
+		"state":                 {f.State},
+		"nonce":                 {f.Nonce},
+		"code_challenge":        {b64(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(p.metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.metadata.AuthorizationEndpoint + sep + q.Encode()
+}
+
+// IDToken holds the claims of a verified ID token.
+type IDToken struct {
+	// Raw is the token as issued, for passing it on to other services.
+	Raw string `json:"-"`
+
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	IssuedAt  int64    `json:"iat"`
+	Nonce     string   `json:"nonce"`
+	// AuthorizedParty is the client the token was issued to, when there
+	// are several audiences.
+	AuthorizedParty   string `json:"azp,omitempty"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     bool   `json:"email_verified,omitempty"`
+}
+
+// audience is a JWT aud claim, a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*a = audience{s}
+		return nil
+	}
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+	*a = ss
+	return nil
+}
+
+func (a audience) contains(s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Exchange redeems the code of a flow at the token endpoint and returns the
+// verified ID token.
+func (p *Provider) Exchange(ctx context.Context, code string, f Flow) (*IDToken, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {f.Verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&res); err != nil {
+		return nil, fmt.Errorf("token endpoint: %s: %v", resp.Status, err)
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("token endpoint: %s: %s", res.Error, res.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || res.IDToken == "" {
+		return nil, fmt.Errorf("token endpoint: %s without an ID token", resp.Status)
+	}
+
+	return p.Verify(res.IDToken, f.Nonce, time.Now())
+}
+
+// Verify checks the signature, issuer, audience, expiry and nonce of an ID
+// token and returns its claims.
+func (p *Provider) Verify(raw, nonce string, now time.Time) (*IDToken, error) {
+	token := &IDToken{Raw: raw}
+	if err := auth.VerifySignature(raw, p.keys, token); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case token.Issuer != p.metadata.Issuer:
+		return nil, fmt.Errorf("ID token of issuer %q", token.Issuer)
+	case token.Subject == "":
+		return nil, errors.New("ID token without a subject")
+	case !token.Audience.contains(p.config.ClientID):
+		return nil, errors.New("ID token for another client")
+	case len(token.Audience) > 1 && token.AuthorizedParty != p.config.ClientID:
+		return nil, errors.New("ID token authorized for another client")
+	case now.Add(-clockSkew).Unix() >= token.ExpiresAt:
+		return nil, auth.ErrExpired
+	case token.IssuedAt > now.Add(clockSkew).Unix():
+		return nil, errors.New("ID token issued in the future")
+	case subtle.ConstantTimeCompare([]byte(token.Nonce), []byte(nonce)) != 1:
+		return nil, errors.New("ID token with another nonce")
+	}
+	return token, nil
+}
+
+// remoteKeys is the issuer's key set, fetched again when a token is signed
+// with a key that is not known yet.
+type remoteKeys struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    auth.Keys
+	fetched time.Time
+}
+
+func (k *remoteKeys) Key(kid string) (crypto.PublicKey, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if key, ok := k.keys[kid]; ok {
+		return key, true
+	}
+	if time.Since(k.fetched) < minKeyRefreshInterval {
+		return nil, false
+	}
+	k.fetched = time.Now()
+
+	var set JSONWebKeySet
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := getJSON(ctx, k.client, k.url, &set); err != nil {
+		return nil, false
+	}
+	keys := make(auth.Keys, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.PublicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	k.keys = keys
+
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Policy holds the permissions of the RPCs of the services, loaded from a
+// JSON file such as:
+//
+//	{"rules": [
+//	  {"method": "/profile.Profile/CreateProfile", "roles": ["admin"]},
+//	  {"method": "/profile.Profile/UpdateProfile", "roles": ["admin", "hotel_manager"], "hotel": "hotel.id"}
+//	]}
+//
+// A method is either a full gRPC method name or a service name followed by
+// "/*", matching every method of the service. Only the listed roles may
+// call a method; a hotel manager only for requests whose hotel field, a
+// dotted path of proto field names, holds hotels they manage. Methods
+// without a rule are open to anyone.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+
+	methods  map[string]*Rule
+	services map[string]*Rule
+}
+
+type Rule struct {
+	Method string   `json:"method"`
+	Roles  []string `json:"roles"`
+	Hotel  string   `json:"hotel,omitempty"`
+}
+
+// LoadPolicy reads and checks a policy file.
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := new(Policy)
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := p.index(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return p, nil
+}
+
+func (p *Policy) index() error {
+	p.methods = make(map[string]*Rule)
+	p.services = make(map[string]*Rule)
+
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if !strings.HasPrefix(r.Method, "/") || strings.Count(r.Method, "/") != 2 {
+			return fmt.Errorf("method %q is not /package.Service/Method", r.Method)
+		}
+		for _, role := range r.Roles {
+			if !ValidRole(role) {
+				return fmt.Errorf("method %s: unknown role %q", r.Method, role)
+			}
+		}
+
+		rules := p.methods
+		key := r.Method
+		if strings.HasSuffix(r.Method, "/*") {
+			rules, key = p.services, strings.TrimSuffix(r.Method, "*")
+		}
+		if _, ok := rules[key]; ok {
+			return fmt.Errorf("method %s has two rules", r.Method)
+		}
+		rules[key] = r
+	}
+	return nil
+}
+
+func (p *Policy) rule(method string) *Rule {
+	if p == nil {
+		return nil
+	}
+	if r, ok := p.methods[method]; ok {
+		return r
+	}
+	return p.services[method[:strings.LastIndex(method, "/")+1]]
+}
+
+// Authorize checks whether the caller in ctx may call method with req. It
+// fails with Unauthenticated for anonymous callers and PermissionDenied for
+// users without the permission. A nil policy allows everything.
+func (p *Policy) Authorize(ctx context.Context, method string, req interface{}) error {
+	r := p.rule(method)
+	if r == nil {
+		return nil
+	}
+
+	id := FromContext(ctx)
+	if id.Username == "" {
+		return status.Errorf(codes.Unauthenticated, "%s requires a logged in user", method)
+	}
+	if !r.allows(id.Role) {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed for role %s", method, id.Role)
+	}
+
+	if id.Role == RoleHotelManager && r.Hotel != "" {
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return status.Errorf(codes.Internal, "%s: request is not a proto message", method)
+		}
+		hotels := fieldValues(msg.ProtoReflect(), strings.Split(r.Hotel, "."))
+		if len(hotels) == 0 {
+			return status.Errorf(codes.PermissionDenied, "%s without a hotel is not allowed for role %s", method, id.Role)
+		}
+		for _, h := range hotels {
+			if !id.Manages(h) {
+				return status.Errorf(codes.PermissionDenied, "%s is not a manager of hotel %s", id.Username, h)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Rule) allows(role string) bool {
+	for _, allowed := range r.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValues returns the non-empty strings at a field path of msg. A
+// repeated string field returns all its elements.
+func fieldValues(msg protoreflect.Message, path []string) []string {
+	fd := msg.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if fd == nil || !msg.Has(fd) {
+		return nil
+	}
+
+	if len(path) > 1 {
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return nil
+		}
+		return fieldValues(msg.Get(fd).Message(), path[1:])
+	}
+
+	if fd.Kind() != protoreflect.StringKind || fd.IsMap() {
+		return nil
+	}
+	if !fd.IsList() {
+		return []string{msg.Get(fd).String()}
+	}
+	list := msg.Get(fd).List()
+	values := make([]string, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		if v := list.Get(i).String(); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// UnaryServerInterceptor rejects the calls the policy does not allow.
+func (p *Policy) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := p.Authorize(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,153 @@
+// Package auth issues and checks the session tokens of logged-in users and
+// carries their identity between services.
+//
+// Tokens are JWTs signed with Ed25519 ("EdDSA"). The user service signs
+// them with the newest key of a rotating key set and publishes the public
+// keys, so the frontend can check tokens without a call per request. Tokens
+// of other issuers may also be signed with RSA ("RS256").
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformed  = errors.New("malformed token")
+	ErrSignature  = errors.New("invalid token signature")
+	ErrUnknownKey = errors.New("token signed with an unknown key")
+	ErrExpired    = errors.New("token expired")
+)
+
+// Claims are the JWT claims of a session token.
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+
+	// Role and Hotels are those of the user when the token was issued.
+	Role   string   `json:"role,omitempty"`
+	Hotels []string `json:"hotels,omitempty"`
+}
+
+// Identity returns the user the token was issued to.
+func (c *Claims) Identity() Identity {
+	role := c.Role
+	if role == "" {
+		role = RoleGuest
+	}
+	return Identity{Username: c.Subject, Role: role, Hotels: c.Hotels}
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// KeySet looks up the public key with a key id, an ed25519.PublicKey or an
+// *rsa.PublicKey.
+type KeySet interface {
+	Key(kid string) (crypto.PublicKey, bool)
+}
+
+// Keys is a fixed KeySet.
+type Keys map[string]crypto.PublicKey
+
+func (k Keys) Key(kid string) (crypto.PublicKey, bool) {
+	key, ok := k[kid]
+	return key, ok
+}
+
+// Sign returns a token for claims signed by key, which keys can later find
+// under kid.
+func Sign(claims Claims, kid string, key ed25519.PrivateKey) (string, error) {
+	h, err := json.Marshal(header{"EdDSA", "JWT", kid})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := encode(h) + "." + encode(c)
+	return signed + "." + encode(ed25519.Sign(key, []byte(signed))), nil
+}
+
+// Verify checks the signature and expiry of a token and returns its claims.
+func Verify(token string, keys KeySet, now time.Time) (*Claims, error) {
+	claims := new(Claims)
+	if err := VerifySignature(token, keys, claims); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, ErrMalformed
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+	return claims, nil
+}
+
+// VerifySignature checks the signature of a token with the key of its key
+// id and decodes its claims into v. The algorithm must suit the key type.
+func VerifySignature(token string, keys KeySet, v interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrMalformed
+	}
+
+	var h header
+	if err := decode(parts[0], &h); err != nil {
+		return ErrMalformed
+	}
+	key, ok := keys.Key(h.Kid)
+	if !ok {
+		return ErrUnknownKey
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrMalformed
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		if h.Alg != "EdDSA" || !ed25519.Verify(key, signed, sig) {
+			return ErrSignature
+		}
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(signed)
+		if h.Alg != "RS256" || rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) != nil {
+			return ErrSignature
+		}
+	default:
+		return ErrUnknownKey
+	}
+
+	if err := decode(parts[1], v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,72 @@
+// Package blobstore stores binary objects, such as uploaded photos, under
+// slash-separated keys like "reviews/42/photo.jpg".
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned for keys without an object.
+	ErrNotFound = errors.New("blobstore: object not found")
+	// ErrInvalidKey is returned for keys that are not relative paths of
+	// letters, digits, '.', '_' and '-', or that have an element starting
+	// with '.'.
+	ErrInvalidKey = errors.New("blobstore: invalid key")
+)
+
+// Store is a place to keep objects in.
+type Store interface {
+	// Put stores the content of r under key, replacing any object there.
+	// Readers of the key never see a partly written object.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get opens the object stored under key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// Open returns the store at location: a file URL or a plain path of a
+// directory of the local file system.
+func Open(location string) (Store, error) {
+	if !strings.Contains(location, "://") {
+		return NewFS(location)
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "file":
+		return NewFS(u.Path)
+	default:
+		return nil, fmt.Errorf("blobstore: unsupported store %q", location)
+	}
+}
+
+// ValidKey reports whether key can name an object.
+func ValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, elem := range strings.Split(key, "/") {
+		if elem == "" || strings.HasPrefix(elem, ".") {
+			return false
+		}
+	}
+	for _, c := range key {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '/' || c == '.' || c == '_' || c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
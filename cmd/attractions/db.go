@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"hotelReservation/mongoindex"
 )
 
 type Restaurant struct {
@@ -70,25 +72,13 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 	}
 	log.Info().Msg("Successfully connected to MongoDB")
 
-	collectionH := client.Database("attractions-db").Collection("hotels")
-	_, err = collectionH.InsertMany(context.TODO(), newPoints)
-	if err != nil {
-		log.Fatal().Msg(err.Error())
-	}
-	log.Info().Msg("Successfully inserted test data into museum DB")
+	insertOnce(client, "hotels", "hotelId", newPoints)
+	log.Info().Msg("Successfully inserted test data into hotel DB")
 
-	collectionR := client.Database("attractions-db").Collection("restaurants")
-	_, err = collectionR.InsertMany(context.TODO(), newRestaurants)
-	if err != nil {
-		log.Fatal().Msg(err.Error())
-	}
+	insertOnce(client, "restaurants", "restaurantId", newRestaurants)
 	log.Info().Msg("Successfully inserted test data into restaurant DB")
 
-	collectionM := client.Database("attractions-db").Collection("museums")
-	_, err = collectionM.InsertMany(context.TODO(), newMuseums)
-	if err != nil {
-		log.Fatal().Msg(err.Error())
-	}
+	insertOnce(client, "museums", "museumId", newMuseums)
 	log.Info().Msg("Successfully inserted test data into museum DB")
 
 	return client, func() {
@@ -98,3 +88,12 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 	}
 
 }
+
+// insertOnce inserts the documents of a collection that are missing, by the
+// unique idField, so that restarts and imports keep their data.
+func insertOnce(client *mongo.Client, name, idField string, docs []interface{}) {
+	collection := client.Database("attractions-db").Collection(name)
+	if err := mongoindex.Seed(context.TODO(), collection, bson.D{{Key: idField, Value: 1}}, docs); err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 
+	"hotelReservation/auth"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/attractions"
@@ -57,10 +58,18 @@ func main() {
 	log.Info().Msgf("Read consul address: %v", result["consulAddress"])
 	log.Info().Msgf("Read jaeger address: %v", result["jaegerAddress"])
 
+	snapshot_max_age, _ := time.ParseDuration(result["SnapshotMaxAge"])
+	watch_index, _ := strconv.ParseBool(result["AttractionsIndexWatch"])
+	poll_interval, _ := time.ParseDuration(result["AttractionsIndexPollInterval"])
+
 	var (
 		// port       = flag.Int("port", 8081, "The server port")
-		jaegeraddr = flag.String("jaegeraddr", result["jaegerAddress"], "Jaeger server addr")
-		consuladdr = flag.String("consuladdr", result["consulAddress"], "Consul address")
+		jaegeraddr     = flag.String("jaegeraddr", result["jaegerAddress"], "Jaeger server addr")
+		consuladdr     = flag.String("consuladdr", result["consulAddress"], "Consul address")
+		snapshotdir    = flag.String("snapshotdir", result["AttractionsSnapshotDir"], "Index snapshot directory, empty to disable")
+		snapshotmaxage = flag.Duration("snapshotmaxage", snapshot_max_age, "Maximum age of a loaded index snapshot")
+		watchindex     = flag.Bool("watchindex", watch_index, "Apply attractions-db changes to the indexes while running")
+		pollinterval   = flag.Duration("pollinterval", poll_interval, "Index reload interval when change streams are unavailable")
 	)
 	flag.Parse()
 
@@ -79,14 +88,32 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Loading auth policy [path: %v]...", result["AuthPolicyPath"])
+	policy, err := auth.LoadPolicy(result["AuthPolicyPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading auth policy: %v", err)
+	}
+
+	log.Info().Msgf("Loading attraction categories [path: %v]...", result["AttractionsCategoriesPath"])
+	categories, err := attractions.LoadCategories(result["AttractionsCategoriesPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading attraction categories: %v", err)
+	}
+
 	srv := attractions.Server{
 		Tracer:         tracer,
 		TracerProvider: tp,
 		// Port:     *port,
-		Registry:    registry,
-		Port:        serv_port,
-		IpAddr:      serv_ip,
-		MongoClient: mongo_session,
+		Registry:       registry,
+		Policy:         policy,
+		Port:           serv_port,
+		IpAddr:         serv_ip,
+		MongoClient:    mongo_session,
+		SnapshotDir:    *snapshotdir,
+		SnapshotMaxAge: *snapshotmaxage,
+		Categories:     categories,
+		WatchIndexes:   *watchindex,
+		PollInterval:   *pollinterval,
 	}
 
 	log.Info().Msg("Starting server...")
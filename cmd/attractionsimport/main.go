@@ -0,0 +1,214 @@
+// Command attractionsimport loads attractions from GeoJSON files into
+// attractions-db. Each Point feature is an attraction with its id as the
+// feature id, and name, category, address, opening_hours, price_level and
+// tags as properties. Attractions are created or updated by id, so files can
+// be imported again after they change. The attractions service indexes them
+// when it restarts.
+//
+// Usage:
+//
+//	attractionsimport [-category name] file.geojson...
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"hotelReservation/services/attractions"
+)
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type     string          `json:"type"`
+	Id       json.RawMessage `json:"id"`
+	Geometry struct {
+		Type        string    `json:"type"`
+		Coordinates []float64 `json:"coordinates"`
+	} `json:"geometry"`
+	Properties struct {
+		Name     string `json:"name"`
+		Category string `json:"category"`
+		Address  string `json:"address"`
+		// OpeningHours maps days to comma separated periods, like
+		// "mon": "11:30-14:30,18:00-22:00".
+		OpeningHours map[string]string `json:"opening_hours"`
+		PriceLevel   int32             `json:"price_level"`
+		Tags         []string          `json:"tags"`
+	} `json:"properties"`
+}
+
+func main() {
+	log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}).With().Timestamp().Caller().Logger()
+
+	jsonFile, err := os.Open("config.json")
+	if err != nil {
+		log.Error().Msgf("Got error while reading config: %v", err)
+	}
+	defer jsonFile.Close()
+
+	byteValue, _ := ioutil.ReadAll(jsonFile)
+
+	var result map[string]string
+	json.Unmarshal([]byte(byteValue), &result)
+
+	var (
+		mongoAddr      = flag.String("mongoaddr", result["AttractionsMongoAddress"], "Attractions MongoDB address")
+		categoriesPath = flag.String("categories", result["AttractionsCategoriesPath"], "Attraction categories")
+		categoryName   = flag.String("category", "", "Category of all features, instead of their category property")
+	)
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: attractionsimport [flags] file.geojson...")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	categories, err := attractions.LoadCategories(*categoriesPath)
+	if err != nil {
+		log.Fatal().Msgf("Failed to load attraction categories: %v", err)
+	}
+	byName := make(map[string]attractions.Category)
+	for _, c := range categories {
+		byName[c.Name] = c
+	}
+	if _, ok := byName[*categoryName]; *categoryName != "" && !ok {
+		log.Fatal().Msgf("Unknown category %q", *categoryName)
+	}
+
+	// read every file before writing, so that a bad file imports nothing
+	places := make(map[string][]attractions.Place)
+	seen := make(map[string]string)
+	for _, path := range flag.Args() {
+		features, err := readFeatures(path)
+		if err != nil {
+			log.Fatal().Msgf("Failed to read %v: %v", path, err)
+		}
+		for i, f := range features {
+			category := *categoryName
+			if category == "" {
+				category = f.Properties.Category
+			}
+			if _, ok := byName[category]; !ok {
+				log.Fatal().Msgf("%v: feature %d has unknown category %q", path, i, category)
+			}
+			p, err := place(f)
+			if err == nil {
+				err = p.Validate()
+			}
+			if err != nil {
+				log.Fatal().Msgf("%v: feature %d: %v", path, i, err)
+			}
+			key := category + "/" + p.Id
+			if prev, ok := seen[key]; ok {
+				log.Fatal().Msgf("%v: %s %s is also in %v", path, category, p.Id, prev)
+			}
+			seen[key] = path
+			places[category] = append(places[category], p)
+		}
+	}
+
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI("mongodb://"+*mongoAddr))
+	if err != nil {
+		log.Fatal().Msgf("Failed to connect to %v: %v", *mongoAddr, err)
+	}
+	defer client.Disconnect(context.TODO())
+
+	names := make([]string, 0, len(places))
+	for name := range places {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		res, err := attractions.Import(context.TODO(), client, byName[name], places[name])
+		if err != nil {
+			log.Fatal().Msgf("Failed to import %s attractions: %v", name, err)
+		}
+		log.Info().Msgf("Imported %d %s attractions: %d new, %d changed",
+			len(places[name]), name, res.UpsertedCount, res.ModifiedCount)
+	}
+}
+
+// readFeatures returns the features of the GeoJSON FeatureCollection at
+// path.
+func readFeatures(path string) ([]feature, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fc featureCollection
+	if err := json.Unmarshal(b, &fc); err != nil {
+		return nil, err
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("type is %q, want FeatureCollection", fc.Type)
+	}
+	return fc.Features, nil
+}
+
+// place returns the attraction of a feature.
+func place(f feature) (attractions.Place, error) {
+	var p attractions.Place
+	if f.Type != "Feature" {
+		return p, fmt.Errorf("type is %q, want Feature", f.Type)
+	}
+	if f.Geometry.Type != "Point" || len(f.Geometry.Coordinates) < 2 {
+		return p, fmt.Errorf("geometry is not a Point")
+	}
+
+	// ids are strings or numbers in GeoJSON
+	var id string
+	if err := json.Unmarshal(f.Id, &id); err != nil {
+		id = string(f.Id)
+	}
+	p.Id = id
+	p.Name = f.Properties.Name
+	p.Lon, p.Lat = f.Geometry.Coordinates[0], f.Geometry.Coordinates[1]
+	p.Address = f.Properties.Address
+	p.PriceLevel = f.Properties.PriceLevel
+	p.Tags = f.Properties.Tags
+
+	days := make([]string, 0, len(f.Properties.OpeningHours))
+	for day := range f.Properties.OpeningHours {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return dayIndex(days[i]) < dayIndex(days[j]) })
+	for _, day := range days {
+		for _, period := range strings.Split(f.Properties.OpeningHours[day], ",") {
+			from, to, ok := strings.Cut(strings.TrimSpace(period), "-")
+			if !ok {
+				return p, fmt.Errorf("%s: opening hours %q are not HH:MM-HH:MM", p.Id, period)
+			}
+			p.OpeningHours = append(p.OpeningHours, attractions.OpeningHours{
+				Day:   strings.ToLower(day),
+				Open:  strings.TrimSpace(from),
+				Close: strings.TrimSpace(to),
+			})
+		}
+	}
+	return p, nil
+}
+
+// dayIndex orders days from Monday, with unknown ones last.
+func dayIndex(day string) int {
+	for i, d := range attractions.Days {
+		if strings.EqualFold(d, day) {
+			return i
+		}
+	}
+	return len(attractions.Days)
+}
@@ -7,8 +7,11 @@ import (
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"hotelReservation/auth/oidc"
+	"hotelReservation/blobstore"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/frontend"
@@ -57,6 +60,16 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Opening image store [location: %v]...", result["FrontendImageStore"])
+	images, err := blobstore.Open(result["FrontendImageStore"])
+	if err != nil {
+		log.Panic().Msgf("Got error while opening image store: %v", err)
+	}
+	maxImageBytes, err := strconv.ParseInt(result["FrontendImageMaxBytes"], 10, 64)
+	if err != nil {
+		log.Panic().Msgf("Got error while reading image size limit: %v", err)
+	}
+
 	srv := &frontend.Server{
 		KnativeDns: knativeDNS,
 		Registry:   registry,
@@ -65,6 +78,15 @@ func main() {
 		ConsulAddr: *consulAddr,
 		Port:       servPort,
 		TracerProvider: tp,
+		OIDC: oidc.Config{
+			Issuer:       result["OIDCIssuer"],
+			ClientID:     result["OIDCClientID"],
+			ClientSecret: result["OIDCClientSecret"],
+			RedirectURL:  result["OIDCRedirectURL"],
+			Scopes:       strings.Fields(result["OIDCScopes"]),
+		},
+		Images:        images,
+		MaxImageBytes: maxImageBytes,
 	}
 
 	log.Info().Msg("Starting server...")
@@ -6,9 +6,12 @@ import (
 	"flag"
 	"io/ioutil"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
+	"hotelReservation/auth"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/geo"
@@ -41,9 +44,17 @@ func main() {
 	servPort, _ := strconv.Atoi(result["GeoPort"])
 	servIP := result["GeoIP"]
 
+	watchIndex, _ := strconv.ParseBool(result["GeoIndexWatch"])
+	pollInterval, _ := time.ParseDuration(result["GeoIndexPollInterval"])
+	snapshotMaxAge, _ := time.ParseDuration(result["SnapshotMaxAge"])
+
 	var (
 		jaegerAddr = flag.String("jaegeraddr", result["jaegerAddress"], "Jaeger address")
 		consulAddr = flag.String("consuladdr", result["consulAddress"], "Consul address")
+		watch      = flag.Bool("watchindex", watchIndex, "Apply geo-db changes to the index while running")
+		poll       = flag.Duration("pollinterval", pollInterval, "Index reload interval when change streams are unavailable")
+		snapPath   = flag.String("snapshotpath", result["GeoSnapshotPath"], "Index snapshot file, empty to disable")
+		snapMaxAge = flag.Duration("snapshotmaxage", snapshotMaxAge, "Maximum age of a loaded index snapshot")
 	)
 	flag.Parse()
 
@@ -62,15 +73,36 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Loading auth policy [path: %v]...", result["AuthPolicyPath"])
+	policy, err := auth.LoadPolicy(result["AuthPolicyPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading auth policy: %v", err)
+	}
+
 	srv := &geo.Server{
-		Port:        servPort,
-		IpAddr:      servIP,
-		Tracer:      tracer,
-		Registry:    registry,
-		MongoClient: mongoClient,
+		Port:           servPort,
+		IpAddr:         servIP,
+		Tracer:         tracer,
+		Registry:       registry,
+		Policy:         policy,
+		MongoClient:    mongoClient,
 		TracerProvider: tp,
+		WatchIndex:     *watch,
+		PollInterval:   *poll,
+		SnapshotPath:   *snapPath,
+		SnapshotMaxAge: *snapMaxAge,
 	}
 
+	// write the last index changes to the snapshot before exiting
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		log.Info().Msg("Shutting down...")
+		srv.Shutdown()
+		os.Exit(0)
+	}()
+
 	log.Info().Msg("Starting server...")
 	log.Fatal().Msg(srv.Run().Error())
 }
@@ -0,0 +1,320 @@
+// Command oidcmock is a minimal OpenID Connect issuer for testing the
+// frontend's /oidc/ login offline. It supports the authorization code flow
+// with PKCE (S256 only), logs in anyone under the username they type, and
+// keeps its signing key and codes in memory.
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"hotelReservation/auth/oidc"
+)
+
+const (
+	keyID    = "oidcmock-1"
+	codeTTL  = time.Minute
+	tokenTTL = 5 * time.Minute
+)
+
+// authorization is a pending authorization code.
+type authorization struct {
+	clientID    string
+	redirectURI string
+	challenge   string
+	nonce       string
+	username    string
+	expires     time.Time
+}
+
+type issuer struct {
+	url          string
+	clientID     string
+	clientSecret string
+	redirectURIs map[string]bool
+	autoLogin    string
+	key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+var loginForm = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><body>
+<h1>Mock identity provider</h1>
+<form method="post" action="/authorize">
+{{range $k, $v := .}}<input type="hidden" name="{{$k}}" value="{{index $v 0}}">
+{{end}}<label>Username <input name="username" autofocus></label>
+<button type="submit">Log in</button>
+</form>
+</body></html>
+`))
+
+func main() {
+	log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}).With().Timestamp().Caller().Logger()
+
+	var (
+		addr         = flag.String("addr", ":5556", "Listen address")
+		issuerURL    = flag.String("issuer", "http://localhost:5556", "Issuer URL, as reachable by browsers and the frontend")
+		clientID     = flag.String("clientid", "hotelreservation", "Client ID of the frontend")
+		clientSecret = flag.String("clientsecret", "", "Client secret of the frontend, empty for a public client")
+		redirects    = flag.String("redirect", "http://localhost:5000/oidc/callback", "Comma separated redirect URIs of the frontend")
+		autoLogin    = flag.String("autologin", "", "Log in as this user without showing the login form")
+	)
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal().Msgf("Failed to generate signing key: %v", err)
+	}
+
+	iss := &issuer{
+		url:          strings.TrimSuffix(*issuerURL, "/"),
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		redirectURIs: make(map[string]bool),
+		autoLogin:    *autoLogin,
+		key:          key,
+		codes:        make(map[string]authorization),
+	}
+	for _, uri := range strings.Split(*redirects, ",") {
+		iss.redirectURIs[strings.TrimSpace(uri)] = true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", iss.discoveryHandler)
+	mux.HandleFunc("/jwks", iss.jwksHandler)
+	mux.HandleFunc("/authorize", iss.authorizeHandler)
+	mux.HandleFunc("/token", iss.tokenHandler)
+
+	log.Info().Msgf("Serving mock OpenID Connect issuer %v on %v", iss.url, *addr)
+	log.Fatal().Msg(http.ListenAndServe(*addr, mux).Error())
+}
+
+func (iss *issuer) discoveryHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, oidc.Metadata{
+		Issuer:                        iss.url,
+		AuthorizationEndpoint:         iss.url + "/authorize",
+		TokenEndpoint:                 iss.url + "/token",
+		JWKSURI:                       iss.url + "/jwks",
+		ResponseTypesSupported:        []string{"code"},
+		SubjectTypesSupported:         []string{"public"},
+		IDTokenSigningAlgValues:       []string{"RS256"},
+		CodeChallengeMethodsSupported: []string{"S256"},
+		ScopesSupported:               []string{"openid", "profile", "email"},
+	})
+}
+
+func (iss *issuer) jwksHandler(w http.ResponseWriter, r *http.Request) {
+	jwk, err := oidc.NewJSONWebKey(keyID, &iss.key.PublicKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, oidc.JSONWebKeySet{Keys: []oidc.JSONWebKey{jwk}})
+}
+
+// authorizeHandler shows the login form, or with a username from the form,
+// the autologin flag or a login_hint, redirects back with a code.
+func (iss *issuer) authorizeHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.Form
+
+	// errors about the client or redirect URI must not redirect
+	if q.Get("client_id") != iss.clientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+	redirectURI := q.Get("redirect_uri")
+	if !iss.redirectURIs[redirectURI] {
+		http.Error(w, "unregistered redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	fail := func(code, description string) {
+		v := url.Values{"error": {code}, "error_description": {description}, "state": {q.Get("state")}}
+		http.Redirect(w, r, redirectURI+"?"+v.Encode(), http.StatusFound)
+	}
+	switch {
+	case q.Get("response_type") != "code":
+		fail("unsupported_response_type", "only the code flow is supported")
+		return
+	case !strings.Contains(" "+q.Get("scope")+" ", " openid "):
+		fail("invalid_scope", "the openid scope is required")
+		return
+	case q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256":
+		fail("invalid_request", "PKCE with S256 is required")
+		return
+	}
+
+	username := q.Get("username")
+	if r.Method == http.MethodGet {
+		username = iss.autoLogin
+		if hint := q.Get("login_hint"); hint != "" {
+			username = hint
+		}
+	}
+	if username == "" {
+		params := url.Values{}
+		for _, k := range []string{"response_type", "client_id", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method"} {
+			params.Set(k, q.Get(k))
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		loginForm.Execute(w, params)
+		return
+	}
+
+	code := randomString()
+	iss.mu.Lock()
+	iss.codes[code] = authorization{
+		clientID:    iss.clientID,
+		redirectURI: redirectURI,
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		username:    username,
+		expires:     time.Now().Add(codeTTL),
+	}
+	iss.mu.Unlock()
+	log.Info().Msgf("Logged in %v", username)
+
+	v := url.Values{"code": {code}, "state": {q.Get("state")}}
+	http.Redirect(w, r, redirectURI+"?"+v.Encode(), http.StatusFound)
+}
+
+// tokenHandler redeems a code for an ID token.
+func (iss *issuer) tokenHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request", err.Error())
+		return
+	}
+
+	clientID, secret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != iss.clientID || subtle.ConstantTimeCompare([]byte(secret), []byte(iss.clientSecret)) != 1 {
+		tokenError(w, "invalid_client", "unknown client or wrong secret")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "only authorization_code is supported")
+		return
+	}
+
+	// codes are used once, even when the exchange fails
+	code := r.PostForm.Get("code")
+	iss.mu.Lock()
+	authz, ok := iss.codes[code]
+	delete(iss.codes, code)
+	iss.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case !ok || time.Now().After(authz.expires):
+		tokenError(w, "invalid_grant", "unknown or expired code")
+		return
+	case authz.clientID != clientID || authz.redirectURI != r.PostForm.Get("redirect_uri"):
+		tokenError(w, "invalid_grant", "code issued to another client or redirect_uri")
+		return
+	case base64.RawURLEncoding.EncodeToString(verifier[:]) != authz.challenge:
+		tokenError(w, "invalid_grant", "code_verifier does not match the code_challenge")
+		return
+	}
+
+	now := time.Now()
+	idToken, err := iss.sign(map[string]interface{}{
+		"iss":                iss.url,
+		"sub":                "mock-" + authz.username,
+		"aud":                clientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(tokenTTL).Unix(),
+		"nonce":              authz.nonce,
+		"name":               authz.username,
+		"preferred_username": authz.username,
+		"email":              authz.username + "@example.com",
+		"email_verified":     true,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(tokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+// sign returns claims as a JWT signed with RS256.
+func (iss *issuer) sign(claims map[string]interface{}) (string, error) {
+	h, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyID})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := b64(h) + "." + b64(c)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, iss.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + b64(sig), nil
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	status := http.StatusBadRequest
+	if code == "invalid_client" {
+		status = http.StatusUnauthorized
+	}
+	writeJSON(w, status, map[string]string{"error": code, "error_description": description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b64(b)
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
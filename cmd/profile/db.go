@@ -2,20 +2,48 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"hotelReservation/mongoindex"
 )
 
 type Hotel struct {
-	Id          string   `bson:"id"`
-	Name        string   `bson:"name"`
-	PhoneNumber string   `bson:"phoneNumber"`
-	Description string   `bson:"description"`
-	Address     *Address `bson:"address"`
+	Id           string   `bson:"id"`
+	Name         string   `bson:"name"`
+	PhoneNumber  string   `bson:"phoneNumber"`
+	Description  string   `bson:"description"`
+	Address      *Address `bson:"address"`
+	HotelDetails `bson:",inline"`
+}
+
+type HotelDetails struct {
+	Amenities    []string    `bson:"amenities"`
+	StarRating   float32     `bson:"starRating"`
+	CheckInTime  string      `bson:"checkInTime"`
+	CheckOutTime string      `bson:"checkOutTime"`
+	RoomTypes    []*RoomType `bson:"roomTypes"`
+}
+
+type RoomType struct {
+	Code         string `bson:"code"`
+	Name         string `bson:"name"`
+	Description  string `bson:"description"`
+	BedType      string `bson:"bedType"`
+	MaxOccupancy int32  `bson:"maxOccupancy"`
+}
+
+type Translation struct {
+	HotelId     string `bson:"hotelId" json:"hotelId"`
+	Locale      string `bson:"locale" json:"locale"`
+	Name        string `bson:"name,omitempty" json:"name,omitempty"`
+	Description string `bson:"description" json:"description"`
 }
 
 type Address struct {
@@ -29,7 +57,7 @@ type Address struct {
 	Lon          float32 `bson:"lon"`
 }
 
-func initializeDatabase(url string) (*mongo.Client, func()) {
+func initializeDatabase(url, localesPath string) (*mongo.Client, func()) {
 	log.Info().Msg("Generating test data...")
 
 	newProfiles := []interface{}{
@@ -48,6 +76,7 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 				37.7867,
 				-122.4112,
 			},
+			hotelDetails("1"),
 		},
 		Hotel{
 			"2",
@@ -64,6 +93,7 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 				37.7854,
 				-122.4005,
 			},
+			hotelDetails("2"),
 		},
 		Hotel{
 			"3",
@@ -80,6 +110,7 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 				37.7834,
 				-122.4071,
 			},
+			hotelDetails("3"),
 		},
 		Hotel{
 			"4",
@@ -96,6 +127,7 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 				37.7936,
 				-122.3930,
 			},
+			hotelDetails("4"),
 		},
 		Hotel{
 			"5",
@@ -112,6 +144,7 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 				37.7831,
 				-122.4181,
 			},
+			hotelDetails("5"),
 		},
 		Hotel{
 			"6",
@@ -128,6 +161,7 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 				37.7863,
 				-122.4015,
 			},
+			hotelDetails("6"),
 		},
 	}
 
@@ -155,6 +189,7 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 					lat,
 					lon,
 				},
+				hotelDetails(hotelID),
 			},
 		)
 	}
@@ -170,15 +205,174 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 	log.Info().Msg("Successfully connected to MongoDB")
 
 	collection := client.Database("profile-db").Collection("hotels")
-	_, err = collection.InsertMany(context.TODO(), newProfiles)
+	// profiles left from an earlier run are kept by the unique index
+	err = mongoindex.Seed(context.TODO(), collection, bson.D{{Key: "id", Value: 1}}, newProfiles)
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+	// profiles start at version 1, see the UpdateProfile RPC
+	_, err = collection.UpdateMany(context.TODO(), bson.M{"version": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"version": 1}})
 	if err != nil {
 		log.Fatal().Msg(err.Error())
 	}
 	log.Info().Msg("Successfully inserted test data into profile DB")
 
+	newTranslations, err := readTranslations(localesPath)
+	if err != nil {
+		log.Fatal().Msgf("Failed to read translations from %v: %v", localesPath, err)
+	}
+	collection = client.Database("profile-db").Collection("locales")
+	// translations left from an earlier run are kept by the unique index
+	err = mongoindex.Seed(context.TODO(), collection, bson.D{{Key: "hotelId", Value: 1}, {Key: "locale", Value: 1}}, newTranslations)
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+	log.Info().Msg("Successfully inserted translations into profile DB")
+
 	return client, func() {
 		if err := client.Disconnect(context.TODO()); err != nil {
 			log.Fatal().Msg(err.Error())
 		}
 	}
 }
+
+var (
+	kingRoom = &RoomType{
+		"KNG",
+		"King Room",
+		"City-view room with a king bed, work desk and rain shower.",
+		"1 King",
+		2,
+	}
+	doubleQueenRoom = &RoomType{
+		"DQQ",
+		"Double Queen Room",
+		"Room with two queen beds, sofa and a bathtub.",
+		"2 Queen",
+		4,
+	}
+	accessibleRoom = &RoomType{
+		"ADA",
+		"Accessible King Room",
+		"Mobility accessible room with a king bed, roll-in shower and grab bars.",
+		"1 King",
+		2,
+	}
+	juniorSuite = &RoomType{
+		"JRS",
+		"Junior Suite",
+		"Corner suite with a king bed, separate sitting area and skyline views.",
+		"1 King",
+		3,
+	}
+	studioRoom = &RoomType{
+		"STU",
+		"Studio",
+		"Compact studio with a queen bed and kitchenette.",
+		"1 Queen",
+		2,
+	}
+)
+
+// hotelDetails returns the amenities, star rating, check-in/out times and
+// room types of a seeded hotel.
+func hotelDetails(hotelID string) HotelDetails {
+	switch hotelID {
+	case "1":
+		return HotelDetails{
+			[]string{"wifi", "restaurant", "bar", "room_service", "fitness_center", "parking", "wheelchair_accessible", "accessible_bathroom", "elevator"},
+			4,
+			"15:00",
+			"12:00",
+			[]*RoomType{kingRoom, doubleQueenRoom, accessibleRoom, juniorSuite},
+		}
+	case "2":
+		return HotelDetails{
+			[]string{"wifi", "bar", "spa", "fitness_center", "parking", "ev_charging", "pet_friendly", "wheelchair_accessible", "elevator", "hearing_accessible"},
+			4.5,
+			"16:00",
+			"11:00",
+			[]*RoomType{kingRoom, doubleQueenRoom, accessibleRoom, juniorSuite},
+		}
+	case "3":
+		return HotelDetails{
+			[]string{"wifi", "restaurant", "bar", "fitness_center", "pet_friendly", "wheelchair_accessible", "elevator"},
+			4,
+			"15:00",
+			"12:00",
+			[]*RoomType{kingRoom, doubleQueenRoom, accessibleRoom},
+		}
+	case "4":
+		return HotelDetails{
+			[]string{"wifi", "restaurant", "room_service", "business_center", "laundry", "parking", "wheelchair_accessible", "elevator"},
+			3.5,
+			"15:00",
+			"12:00",
+			[]*RoomType{kingRoom, doubleQueenRoom, accessibleRoom},
+		}
+	case "5":
+		return HotelDetails{
+			[]string{"wifi", "restaurant", "bar", "pool", "spa", "fitness_center", "room_service", "parking", "ev_charging", "airport_shuttle", "wheelchair_accessible", "accessible_bathroom", "elevator", "hearing_accessible"},
+			5,
+			"16:00",
+			"12:00",
+			[]*RoomType{kingRoom, doubleQueenRoom, accessibleRoom, juniorSuite},
+		}
+	case "6":
+		return HotelDetails{
+			[]string{"wifi", "air_conditioning", "laundry", "elevator"},
+			3,
+			"14:00",
+			"11:00",
+			[]*RoomType{studioRoom, doubleQueenRoom},
+		}
+	}
+
+	// the remaining hotels get a mix of amenities picked by hotel number
+	i, _ := strconv.Atoi(hotelID)
+	details := HotelDetails{
+		[]string{"wifi", "air_conditioning", "elevator"},
+		float32(3 + i%3),
+		"15:00",
+		"11:00",
+		[]*RoomType{kingRoom, doubleQueenRoom},
+	}
+	if i%2 == 0 {
+		details.Amenities = append(details.Amenities, "parking")
+	}
+	if i%3 == 0 {
+		details.Amenities = append(details.Amenities, "pool", "fitness_center")
+	}
+	if i%4 == 0 {
+		details.Amenities = append(details.Amenities, "pet_friendly")
+		details.CheckInTime = "16:00"
+	}
+	if i%5 != 0 {
+		details.Amenities = append(details.Amenities, "wheelchair_accessible")
+		details.RoomTypes = append(details.RoomTypes, accessibleRoom)
+	}
+	if details.StarRating == 5 {
+		details.Amenities = append(details.Amenities, "spa", "room_service")
+		details.CheckOutTime = "12:00"
+		details.RoomTypes = append(details.RoomTypes, juniorSuite)
+	}
+	return details
+}
+
+// readTranslations returns the translations of the JSON array at path.
+func readTranslations(path string) ([]interface{}, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var translations []Translation
+	if err := json.Unmarshal(b, &translations); err != nil {
+		return nil, err
+	}
+
+	docs := make([]interface{}, len(translations))
+	for i, t := range translations {
+		docs[i] = t
+	}
+	return docs, nil
+}
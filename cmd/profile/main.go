@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"hotelReservation/auth"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/profile"
@@ -35,7 +36,7 @@ func main() {
 	json.Unmarshal([]byte(byteValue), &result)
 
 	log.Info().Msg("Initializing DB connection...")
-	mongoClient, mongoClose := initializeDatabase(result["ProfileMongoAddress"])
+	mongoClient, mongoClose := initializeDatabase(result["ProfileMongoAddress"], result["ProfileLocalesPath"])
 	defer mongoClose()
 
 	log.Info().Msgf("Read profile memcashed address: %v", result["ProfileMemcAddress"])
@@ -67,13 +68,20 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Loading auth policy [path: %v]...", result["AuthPolicyPath"])
+	policy, err := auth.LoadPolicy(result["AuthPolicyPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading auth policy: %v", err)
+	}
+
 	srv := &profile.Server{
-		Port:        servPort,
-		IpAddr:      servIP,
-		Tracer:      tracer,
-		Registry:    registry,
-		MongoClient: mongoClient,
-		MemcClient:  memcClient,
+		Port:           servPort,
+		IpAddr:         servIP,
+		Tracer:         tracer,
+		Registry:       registry,
+		Policy:         policy,
+		MongoClient:    mongoClient,
+		MemcClient:     memcClient,
 		TracerProvider: tp,
 	}
 
@@ -10,6 +10,7 @@ import (
 
 	"strconv"
 
+	"hotelReservation/auth"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/recommendation"
@@ -41,10 +42,15 @@ func main() {
 
 	servPort, _ := strconv.Atoi(result["RecommendPort"])
 	servIP := result["RecommendIP"]
+	topK, _ := strconv.Atoi(result["RecommendTopK"])
+	reloadInterval, _ := time.ParseDuration(result["RecommendReloadInterval"])
 
 	var (
 		jaegerAddr = flag.String("jaegeraddr", result["jaegerAddress"], "Jaeger address")
 		consulAddr = flag.String("consuladdr", result["consulAddress"], "Consul address")
+		k          = flag.Int("topk", topK, "Number of recommended hotels when a request does not set one")
+		modelPath  = flag.String("modelpath", result["RecommendModelPath"], "Collaborative filtering model written by recommendationtrain")
+		reload     = flag.Duration("reloadinterval", reloadInterval, "Dataset reload interval, 0 to reload on request only")
 	)
 	flag.Parse()
 
@@ -56,6 +62,12 @@ func main() {
 	defer oteltracing.Shutdown(context.Background())
 	log.Info().Msg("Jaeger agent initialized")
 
+	mp, err := oteltracing.InitMetrics("recommendation-hotel-hotelres")
+	if err != nil {
+		log.Panic().Msgf("Got error while initializing metrics: %v", err)
+	}
+	defer oteltracing.ShutdownMetrics(context.Background())
+
 	log.Info().Msgf("Initializing consul agent [host: %v]...", *consulAddr)
 	registry, err := registry.NewClient(*consulAddr)
 	if err != nil {
@@ -63,13 +75,24 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Loading auth policy [path: %v]...", result["AuthPolicyPath"])
+	policy, err := auth.LoadPolicy(result["AuthPolicyPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading auth policy: %v", err)
+	}
+
 	srv := &recommendation.Server{
-		Port:        servPort,
-		IpAddr:      servIP,
-		Tracer:      tracer,
-		Registry:    registry,
-		MongoClient: mongoClient,
+		Port:           servPort,
+		IpAddr:         servIP,
+		Tracer:         tracer,
+		Registry:       registry,
+		Policy:         policy,
+		MongoClient:    mongoClient,
 		TracerProvider: tp,
+		MeterProvider:  mp,
+		TopK:           *k,
+		ModelPath:      *modelPath,
+		ReloadInterval: *reload,
 	}
 
 	log.Info().Msg("Starting server...")
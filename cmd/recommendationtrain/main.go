@@ -0,0 +1,65 @@
+// Command recommendationtrain builds the collaborative filtering model of
+// the recommendation service from the reservation history in
+// reservation-db and writes it to the path the service loads it from.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"hotelReservation/services/recommendation"
+)
+
+func main() {
+	log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}).With().Timestamp().Caller().Logger()
+
+	jsonFile, err := os.Open("config.json")
+	if err != nil {
+		log.Error().Msgf("Got error while reading config: %v", err)
+	}
+	defer jsonFile.Close()
+
+	byteValue, _ := ioutil.ReadAll(jsonFile)
+
+	var result map[string]string
+	json.Unmarshal([]byte(byteValue), &result)
+
+	var (
+		mongoAddr = flag.String("mongoaddr", result["ReserveMongoAddress"], "Reservation MongoDB address")
+		out       = flag.String("out", result["RecommendModelPath"], "Path to write the model to")
+	)
+	flag.Parse()
+
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI("mongodb://"+*mongoAddr))
+	if err != nil {
+		log.Fatal().Msgf("Failed to connect to %v: %v", *mongoAddr, err)
+	}
+	defer client.Disconnect(context.TODO())
+
+	collection := client.Database("reservation-db").Collection("reservation")
+	opts := options.Find().SetProjection(bson.M{"hotelId": 1, "customerName": 1})
+	curr, err := collection.Find(context.TODO(), bson.D{}, opts)
+	if err != nil {
+		log.Fatal().Msgf("Failed get reservation data: %v", err)
+	}
+	var bookings []recommendation.Booking
+	if err := curr.All(context.TODO(), &bookings); err != nil {
+		log.Fatal().Msgf("Failed get reservation data: %v", err)
+	}
+
+	model := recommendation.Train(bookings)
+	if err := model.Save(*out); err != nil {
+		log.Fatal().Msgf("Failed to write model to %v: %v", *out, err)
+	}
+	log.Info().Msgf("Wrote model of %d customers and %d hotels from %d reservations to %v",
+		len(model.History), len(model.Neighbors), len(bookings), *out)
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -28,6 +29,18 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 
 	newReservations := []interface{}{
 		Reservation{"4", "Alice", "2015-04-09", "2015-04-10", 1},
+		Reservation{"1", "Alice", "2015-05-14", "2015-05-15", 1},
+		Reservation{"4", "Bob", "2015-03-02", "2015-03-03", 1},
+		Reservation{"2", "Bob", "2015-06-20", "2015-06-21", 1},
+		Reservation{"6", "Bob", "2015-08-11", "2015-08-12", 2},
+		Reservation{"1", "Carol", "2015-02-17", "2015-02-18", 1},
+		Reservation{"2", "Carol", "2015-07-03", "2015-07-04", 1},
+		Reservation{"5", "Carol", "2015-09-25", "2015-09-26", 1},
+		Reservation{"3", "Dave", "2015-01-08", "2015-01-09", 1},
+		Reservation{"5", "Dave", "2015-04-22", "2015-04-23", 1},
+		Reservation{"9", "Dave", "2015-10-30", "2015-10-31", 1},
+		Reservation{"6", "Erin", "2015-05-01", "2015-05-02", 3},
+		Reservation{"9", "Erin", "2015-11-12", "2015-11-13", 1},
 	}
 
 	newNumbers := []interface{}{
@@ -77,6 +90,14 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 	}
 	log.Info().Msg("Successfully inserted test data into reservation DB")
 
+	// look up the stays of a customer at a hotel for reviews
+	_, err = resCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "customerName", Value: 1}, {Key: "hotelId", Value: 1}, {Key: "outDate", Value: -1}},
+	})
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+
 	return client, func() {
 		if err := client.Disconnect(context.TODO()); err != nil {
 			log.Fatal().Msg(err.Error())
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"hotelReservation/auth"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/reservation"
@@ -67,13 +68,20 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Loading auth policy [path: %v]...", result["AuthPolicyPath"])
+	policy, err := auth.LoadPolicy(result["AuthPolicyPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading auth policy: %v", err)
+	}
+
 	srv := &reservation.Server{
-		Tracer:      tracer,
-		Registry:    registry,
-		Port:        servPort,
-		IpAddr:      servIP,
-		MongoClient: mongoClient,
-		MemcClient:  memcClient,
+		Tracer:         tracer,
+		Registry:       registry,
+		Policy:         policy,
+		Port:           servPort,
+		IpAddr:         servIP,
+		MongoClient:    mongoClient,
+		MemcClient:     memcClient,
 		TracerProvider: tp,
 	}
 
@@ -4,17 +4,20 @@ import (
 	"context"
 	"fmt"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"hotelReservation/mongoindex"
+	"hotelReservation/services/review"
 )
 
 type Review struct {
-	ReviewId    string  `bson:"reviewId"`
-	HotelId     string  `bson:"hotelId"`
-	Name        string  `bson:"name"`
-	Rating      float32 `bson:"rating"`
-	Description string  `bson:"description"`
-	Image       *Image  `bson:"images"`
+	ReviewId    string   `bson:"reviewId"`
+	HotelId     string   `bson:"hotelId"`
+	Name        string   `bson:"name"`
+	Rating      float32  `bson:"rating"`
+	Description string   `bson:"description"`
+	Images      []*Image `bson:"images"`
 }
 
 type Image struct {
@@ -31,54 +34,54 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 			"Person 1",
 			3.4,
 			"A 6-minute walk from Union Square and 4 minutes from a Muni Metro station, this luxury hotel designed by Philippe Starck features an artsy furniture collection in the lobby, including work by Salvador Dali.",
-			&Image{
+			[]*Image{{
 				"some url",
-				false}},
+				false}}},
 		&Review{
 			"2",
 			"1",
 			"Person 2",
 			4.4,
 			"A 6-minute walk from Union Square and 4 minutes from a Muni Metro station, this luxury hotel designed by Philippe Starck features an artsy furniture collection in the lobby, including work by Salvador Dali.",
-			&Image{
+			[]*Image{{
 				"some url",
-				false}},
+				false}}},
 		&Review{
 			"3",
 			"1",
 			"Person 3",
 			4.2,
 			"A 6-minute walk from Union Square and 4 minutes from a Muni Metro station, this luxury hotel designed by Philippe Starck features an artsy furniture collection in the lobby, including work by Salvador Dali.",
-			&Image{
+			[]*Image{{
 				"some url",
-				false}},
+				false}}},
 		&Review{
 			"4",
 			"1",
 			"Person 4",
 			3.9,
 			"A 6-minute walk from Union Square and 4 minutes from a Muni Metro station, this luxury hotel designed by Philippe Starck features an artsy furniture collection in the lobby, including work by Salvador Dali.",
-			&Image{
+			[]*Image{{
 				"some url",
-				false}},
+				false}}},
 		&Review{
 			"5",
 			"2",
 			"Person 5",
 			4.2,
 			"A 6-minute walk from Union Square and 4 minutes from a Muni Metro station, this luxury hotel designed by Philippe Starck features an artsy furniture collection in the lobby, including work by Salvador Dali.",
-			&Image{
+			[]*Image{{
 				"some url",
-				false}},
+				false}}},
 		&Review{
 			"6",
 			"2",
 			"Person 6",
 			3.7,
 			"A 6-minute walk from Union Square and 4 minutes from a Muni Metro station, this luxury hotel designed by Philippe Starck features an artsy furniture collection in the lobby, including work by Salvador Dali.",
-			&Image{
+			[]*Image{{
 				"some url",
-				false}},
+				false}}},
 	}
 
 	uri := fmt.Sprintf("mongodb://%s", url)
@@ -92,12 +95,80 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 	log.Info().Msg("Successfully connected to MongoDB")
 
 	collection := client.Database("review-db").Collection("reviews")
-	_, err = collection.InsertMany(context.TODO(), newReviews)
+	// the reviews are already there after a restart
+	err = mongoindex.Seed(context.TODO(), collection, bson.D{{Key: "reviewId", Value: 1}}, newReviews)
 	if err != nil {
 		log.Fatal().Msg(err.Error())
 	}
 	log.Info().Msg("Successfully inserted test data into rate DB")
 
+	// reviews used to have a single image
+	_, err = collection.UpdateMany(context.TODO(),
+		bson.M{"images": bson.M{"$type": "object", "$not": bson.M{"$type": "array"}}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"images": bson.A{"$images"}}}}},
+	)
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+
+	// reviews are paged by their sort keys, which seeded reviews lack
+	_, err = collection.UpdateMany(context.TODO(),
+		bson.M{"createdAt": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"createdAt": bson.M{"$toDate": "$_id"}}}}},
+	)
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+	_, err = collection.UpdateMany(context.TODO(),
+		bson.M{"helpfulCount": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"helpfulCount": 0}},
+	)
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+
+	// users review each hotel once
+	err = mongoindex.EnsureUnique(context.TODO(), collection, mongo.IndexModel{
+		Keys: bson.D{{Key: "hotelId", Value: 1}, {Key: "username", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"username": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		log.Error().Msgf("Failed to create the hotel and username index: %v", err)
+	}
+	// reviews are paged in the orders of GetReviews, and moderators go
+	// through the reviews of a status oldest first
+	_, err = collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "hotelId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "reviewId", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "hotelId", Value: 1}, {Key: "rating", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "reviewId", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "hotelId", Value: 1}, {Key: "helpfulCount", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "reviewId", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+		},
+	})
+	if err != nil {
+		log.Error().Msgf("Failed to create the review paging indexes: %v", err)
+	}
+
+	// users vote once on each review
+	err = mongoindex.EnsureUnique(context.TODO(), client.Database("review-db").Collection("votes"), mongo.IndexModel{
+		Keys:    bson.D{{Key: "reviewId", Value: 1}, {Key: "username", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Error().Msgf("Failed to create the vote index: %v", err)
+	}
+
+	if err := review.BackfillRatings(context.TODO(), client); err != nil {
+		log.Fatal().Msgf("Failed to backfill rating summaries: %v", err)
+	}
+
 	return client, func() {
 		if err := client.Disconnect(context.TODO()); err != nil {
 			log.Fatal().Msg(err.Error())
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 
+	"hotelReservation/auth"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/review"
@@ -79,14 +80,21 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Loading auth policy [path: %v]...", result["AuthPolicyPath"])
+	policy, err := auth.LoadPolicy(result["AuthPolicyPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading auth policy: %v", err)
+	}
+
 	srv := review.Server{
 		Tracer: tracer,
 		// Port:     *port,
-		Registry:    registry,
-		Port:        serv_port,
-		IpAddr:      serv_ip,
-		MongoClient: mongo_session,
-		MemcClient:  memc_client,
+		Registry:       registry,
+		Policy:         policy,
+		Port:           serv_port,
+		IpAddr:         serv_ip,
+		MongoClient:    mongo_session,
+		MemcClient:     memc_client,
 		TracerProvider: tp,
 	}
 
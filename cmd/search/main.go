@@ -10,6 +10,7 @@ import (
 
 	"strconv"
 
+	"hotelReservation/auth"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/search"
@@ -60,13 +61,20 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Loading auth policy [path: %v]...", result["AuthPolicyPath"])
+	policy, err := auth.LoadPolicy(result["AuthPolicyPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading auth policy: %v", err)
+	}
+
 	srv := &search.Server{
-		Tracer:     tracer,
-		Port:       servPort,
-		IpAddr:     servIP,
-		ConsulAddr: *consulAddr,
-		KnativeDns: knativeDNS,
-		Registry:   registry,
+		Tracer:         tracer,
+		Port:           servPort,
+		IpAddr:         servIP,
+		ConsulAddr:     *consulAddr,
+		KnativeDns:     knativeDNS,
+		Registry:       registry,
+		Policy:         policy,
 		TracerProvider: tp,
 	}
 
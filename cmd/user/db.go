@@ -4,16 +4,33 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"hotelReservation/mongoindex"
+	"hotelReservation/services/user"
 )
 
 type User struct {
-	Username string `bson:"username"`
-	Password string `bson:"password"`
+	Username string   `bson:"username"`
+	Password string   `bson:"password"`
+	Role     string   `bson:"role"`
+	Hotels   []string `bson:"hotels,omitempty"`
+}
+
+// staffAccounts are the admin and hotel manager accounts, created when the
+// environment variable env holds their password.
+var staffAccounts = []struct {
+	username, role, env string
+	hotels              []string
+}{
+	{"admin", "admin", "USER_ADMIN_PASSWORD", nil},
+	{"manager_1", "hotel_manager", "USER_MANAGER_1_PASSWORD", []string{"1", "2", "3"}},
+	{"manager_2", "hotel_manager", "USER_MANAGER_2_PASSWORD", []string{"4", "5", "6"}},
 }
 
 func initializeDatabase(url string) (*mongo.Client, func()) {
@@ -33,6 +50,8 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 		newUsers = append(newUsers, User{
 			fmt.Sprintf("Cornell_%x", suffix),
 			fmt.Sprintf("%x", sum),
+			"guest",
+			nil,
 		})
 	}
 
@@ -47,15 +66,81 @@ func initializeDatabase(url string) (*mongo.Client, func()) {
 	log.Info().Msg("Successfully connected to MongoDB")
 
 	collection := client.Database("user-db").Collection("user")
-	_, err = collection.InsertMany(context.TODO(), newUsers)
+	// users of an OpenID Connect issuer are created once
+	err = mongoindex.EnsureUnique(context.TODO(), collection, mongo.IndexModel{
+		Keys: bson.D{{Key: "issuer", Value: 1}, {Key: "subject", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"subject": bson.M{"$type": "string"}}),
+	})
+	if err != nil {
+		log.Error().Msgf("Failed to create the issuer and subject index: %v", err)
+	}
+	// users left from an earlier run are kept by the unique index
+	err = mongoindex.Seed(context.TODO(), collection, bson.D{{Key: "username", Value: 1}}, newUsers)
 	if err != nil {
 		log.Fatal().Msg(err.Error())
 	}
 	log.Info().Msg("Successfully inserted test data into user DB")
 
+	if err := seedStaff(context.TODO(), collection); err != nil {
+		log.Fatal().Msgf("Failed to create the staff accounts: %v", err)
+	}
+
+	// replicas rotating at the same time agree on one key per period
+	err = mongoindex.EnsureUnique(context.TODO(), client.Database("user-db").Collection("keys"), mongo.IndexModel{
+		Keys:    bson.D{{Key: "kid", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Error().Msgf("Failed to create the key id index: %v", err)
+	}
+
 	return client, func() {
 		if err := client.Disconnect(context.TODO()); err != nil {
 			log.Fatal().Msg(err.Error())
 		}
 	}
 }
+
+// seedStaff creates the staff accounts whose password is set. Accounts that
+// still have the password earlier versions seeded, the username twice, get
+// the set password, or are removed when there is none.
+func seedStaff(ctx context.Context, collection *mongo.Collection) error {
+	for _, staff := range staffAccounts {
+		sum := sha256.Sum256([]byte(staff.username + staff.username))
+		seeded := bson.M{"username": staff.username, "password": fmt.Sprintf("%x", sum)}
+
+		password := os.Getenv(staff.env)
+		if password == "" {
+			res, err := collection.DeleteOne(ctx, seeded)
+			if err != nil {
+				return err
+			}
+			if res.DeletedCount > 0 {
+				log.Warn().Msgf("Removed %s with its default password, set %s to create it again", staff.username, staff.env)
+			}
+			continue
+		}
+
+		hash, err := user.HashPassword(password)
+		if err != nil {
+			return fmt.Errorf("%s: %v", staff.env, err)
+		}
+		if _, err := collection.UpdateOne(ctx, seeded, bson.M{"$set": bson.M{"password": hash}}); err != nil {
+			return err
+		}
+		account := bson.M{"password": hash, "role": staff.role}
+		if staff.hotels != nil {
+			account["hotels"] = staff.hotels
+		}
+		_, err = collection.UpdateOne(ctx,
+			bson.M{"username": staff.username},
+			bson.M{"$setOnInsert": account},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"time"
 
+	"hotelReservation/auth"
+	"hotelReservation/auth/oidc"
 	oteltracing "hotelReservation/oteltracing"
 	"hotelReservation/registry"
 	"hotelReservation/services/user"
@@ -39,12 +41,29 @@ func main() {
 	mongoClient, mongoClose := initializeDatabase(result["UserMongoAddress"])
 	defer mongoClose()
 
+	log.Info().Msgf("Read user memcashed address: %v", result["UserMemcAddress"])
+	log.Info().Msg("Initializing Memcashed client...")
+	memcClient := tune.NewMemCClient2(result["UserMemcAddress"])
+	log.Info().Msg("Success")
+
 	servPort, _ := strconv.Atoi(result["UserPort"])
 	servIP := result["UserIP"]
+	pollInterval, _ := time.ParseDuration(result["UserPollInterval"])
+	tokenTTL, _ := time.ParseDuration(result["UserTokenTTL"])
+	keyRotation, _ := time.ParseDuration(result["UserKeyRotation"])
+	maxFailures, _ := strconv.Atoi(result["UserMaxLoginFailures"])
+	maxIPFailures, _ := strconv.Atoi(result["UserMaxIPLoginFailures"])
+	lockout, _ := time.ParseDuration(result["UserLockoutDuration"])
 
 	var (
 		jaegerAddr = flag.String("jaegeraddr", result["jaegerAddress"], "Jaeger address")
 		consulAddr = flag.String("consuladdr", result["consulAddress"], "Consul address")
+		poll       = flag.Duration("pollinterval", pollInterval, "User reload interval when change streams are unavailable")
+		ttl        = flag.Duration("tokenttl", tokenTTL, "Session token lifetime")
+		rotation   = flag.Duration("keyrotation", keyRotation, "Session token signing key rotation interval")
+		failures   = flag.Int("maxfailures", maxFailures, "Failed logins of a user before a lockout")
+		ipFailures = flag.Int("maxipfailures", maxIPFailures, "Failed logins from a client address before a lockout")
+		lockoutFor = flag.Duration("lockout", lockout, "Login lockout duration")
 	)
 	flag.Parse()
 
@@ -63,13 +82,31 @@ func main() {
 	}
 	log.Info().Msg("Consul agent initialized")
 
+	log.Info().Msgf("Loading auth policy [path: %v]...", result["AuthPolicyPath"])
+	policy, err := auth.LoadPolicy(result["AuthPolicyPath"])
+	if err != nil {
+		log.Panic().Msgf("Got error while loading auth policy: %v", err)
+	}
+
 	srv := &user.Server{
-		Port:        servPort,
-		IpAddr:      servIP,
-		Tracer:      tracer,
-		Registry:    registry,
-		MongoClient: mongoClient,
-		TracerProvider: tp,
+		Port:               servPort,
+		IpAddr:             servIP,
+		Tracer:             tracer,
+		Registry:           registry,
+		Policy:             policy,
+		MongoClient:        mongoClient,
+		TracerProvider:     tp,
+		PollInterval:       *poll,
+		TokenTTL:           *ttl,
+		KeyRotation:        *rotation,
+		MemcClient:         memcClient,
+		MaxLoginFailures:   *failures,
+		MaxIPLoginFailures: *ipFailures,
+		LockoutDuration:    *lockoutFor,
+		OIDC: oidc.Config{
+			Issuer:   result["OIDCIssuer"],
+			ClientID: result["OIDCClientID"],
+		},
 	}
 
 	log.Info().Msg("Starting server...")
@@ -6,6 +6,7 @@ import (
 	// "time"
 
 	// "hotelReservation/tls"
+	"hotelReservation/auth"
 	consul "github.com/hashicorp/consul/api"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
@@ -37,6 +38,7 @@ func WithBalancer(registry *consul.Client) DialOption {
 }
 
 // Dial returns a load balanced grpc client conn with tracing interceptor
+// that forwards the identity of the calling user
 func Dial(name string, ctx context.Context, tp trace.TracerProvider, opts ...DialOption) (*grpc.ClientConn, error) {
 	dials := []grpc.DialOption{
         grpc.WithTransportCredentials(insecure.NewCredentials()),
@@ -45,6 +47,7 @@ func Dial(name string, ctx context.Context, tp trace.TracerProvider, opts ...Dia
                 otelgrpc.WithTracerProvider(tp),
                 otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
             ),
+            auth.UnaryClientInterceptor(),
         ),
         grpc.WithChainStreamInterceptor(
             otelgrpc.StreamClientInterceptor(
@@ -0,0 +1,108 @@
+// Package mongoindex creates the unique indexes the services seed and look
+// up their collections by.
+package mongoindex
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureUnique creates the unique index model, whose Keys must be a bson.D,
+// on collection. Collections seeded before the index existed can hold
+// duplicate keys, which make the creation fail. Those duplicates are removed,
+// keeping the earliest inserted document of each key, which is the one
+// lookups by the key found so far, and the creation is retried.
+func EnsureUnique(ctx context.Context, collection *mongo.Collection, model mongo.IndexModel) error {
+	_, err := collection.Indexes().CreateOne(ctx, model)
+	if err == nil || !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	removed, err := removeDuplicates(ctx, collection, model)
+	if err != nil {
+		return err
+	}
+	log.Warn().Msgf("Removed %d documents with duplicate %v from %s.%s",
+		removed, model.Keys, collection.Database().Name(), collection.Name())
+	_, err = collection.Indexes().CreateOne(ctx, model)
+	return err
+}
+
+// removeDuplicates deletes all but the first document of every key of the
+// index model, and returns the number of deleted documents.
+func removeDuplicates(ctx context.Context, collection *mongo.Collection, model mongo.IndexModel) (int64, error) {
+	keys, ok := model.Keys.(bson.D)
+	if !ok {
+		return 0, fmt.Errorf("index keys %v are not a bson.D", model.Keys)
+	}
+	// key paths may contain dots, which group field names cannot
+	key := bson.D{}
+	for i, k := range keys {
+		key = append(key, bson.E{Key: "k" + strconv.Itoa(i), Value: "$" + k.Key})
+	}
+	var filter interface{} = bson.D{}
+	if model.Options != nil && model.Options.PartialFilterExpression != nil {
+		filter = model.Options.PartialFilterExpression
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: key},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "ids.1", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var removed int64
+	for cursor.Next(ctx) {
+		var group struct {
+			Ids []interface{} `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return removed, err
+		}
+		res, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": group.Ids[1:]}})
+		if err != nil {
+			return removed, err
+		}
+		removed += res.DeletedCount
+	}
+	return removed, cursor.Err()
+}
+
+// Seed inserts the docs collection does not hold yet, telling them apart by
+// a unique index on keys. If the index cannot be created, the error is
+// logged and docs are only inserted into an empty collection, so that
+// restarts do not insert them again.
+func Seed(ctx context.Context, collection *mongo.Collection, keys bson.D, docs []interface{}) error {
+	err := EnsureUnique(ctx, collection, mongo.IndexModel{Keys: keys, Options: options.Index().SetUnique(true)})
+	if err != nil {
+		log.Error().Msgf("Failed to create unique index %v on %s.%s, seeding only if it is empty: %v",
+			keys, collection.Database().Name(), collection.Name(), err)
+		n, err := collection.CountDocuments(ctx, bson.D{}, options.Count().SetLimit(1))
+		if err != nil || n > 0 {
+			return err
+		}
+		_, err = collection.InsertMany(ctx, docs)
+		return err
+	}
+
+	_, err = collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
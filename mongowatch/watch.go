@@ -0,0 +1,110 @@
+package mongowatch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultPollInterval is the poll interval of watchers without one.
+const DefaultPollInterval = 30 * time.Second
+
+// Watcher keeps data loaded from a collection, like an index or a map, in
+// sync with changes made through other replicas or tools. It follows the
+// collection's change stream and falls back to polling when change streams
+// are unavailable, e.g. on a standalone mongod.
+type Watcher struct {
+	Collection *mongo.Collection
+	// PollInterval is how often Reload is called when change streams are
+	// unavailable. DefaultPollInterval is used if it is not positive.
+	PollInterval time.Duration
+	// Apply applies the inserted or updated document of a change stream
+	// event. Delete events only carry the document _id, so they and
+	// collection-level events cause a Reload instead.
+	Apply func(ctx context.Context, doc bson.Raw) error
+	// Reload loads the whole collection again.
+	Reload func(ctx context.Context) error
+}
+
+// changeEvent is the subset of a change stream event used by the watcher.
+type changeEvent struct {
+	OperationType string   `bson:"operationType"`
+	FullDocument  bson.Raw `bson:"fullDocument"`
+}
+
+// Run watches the collection until ctx is done.
+func (w *Watcher) Run(ctx context.Context) {
+	name := w.Collection.Database().Name() + "." + w.Collection.Name()
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	stream, err := w.Collection.Watch(ctx, mongo.Pipeline{}, opts)
+	if err != nil {
+		log.Warn().Msgf("%s change stream unavailable, polling every %v: %v", name, w.pollInterval(), err)
+		w.poll(ctx, name)
+		return
+	}
+	defer stream.Close(ctx)
+	log.Info().Msgf("Watching %s for changes", name)
+
+	for stream.Next(ctx) {
+		var event changeEvent
+		if err := stream.Decode(&event); err != nil {
+			log.Error().Msgf("Failed to decode %s change event: %v", name, err)
+			continue
+		}
+		w.applyChange(ctx, name, event)
+	}
+
+	if err := stream.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		log.Warn().Msgf("%s change stream failed, polling every %v: %v", name, w.pollInterval(), err)
+		w.poll(ctx, name)
+	}
+}
+
+func (w *Watcher) applyChange(ctx context.Context, name string, event changeEvent) {
+	switch event.OperationType {
+	case "insert", "update", "replace":
+		if event.FullDocument == nil {
+			// deleted before the update was looked up
+			return
+		}
+		log.Trace().Msgf("%s change %s", name, event.OperationType)
+		if err := w.Apply(ctx, event.FullDocument); err != nil {
+			log.Error().Msgf("Failed to apply %s change: %v", name, err)
+		}
+	default:
+		log.Trace().Msgf("%s change %s, reloading", name, event.OperationType)
+		if err := w.Reload(ctx); err != nil {
+			log.Error().Msgf("Failed to reload %s: %v", name, err)
+		}
+	}
+}
+
+// poll calls Reload every PollInterval until ctx is done.
+func (w *Watcher) poll(ctx context.Context, name string) {
+	ticker := time.NewTicker(w.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Reload(ctx); err != nil {
+				log.Error().Msgf("Failed to reload %s: %v", name, err)
+			}
+		}
+	}
+}
+
+func (w *Watcher) pollInterval() time.Duration {
+	if w.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return w.PollInterval
+}
@@ -0,0 +1,52 @@
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// metricInterval is how often metrics are exported.
+const metricInterval = 30 * time.Second
+
+// InitMetrics initializes OpenTelemetry metrics with an OTLP exporter, sent
+// to the same collector as the traces, and sets the global meter provider.
+func InitMetrics(serviceName string) (*sdkmetric.MeterProvider, error) {
+	ctx := context.Background()
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+		),
+		resource.WithSchemaURL(semconv.SchemaURL),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	exporter, err := otlpmetricgrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(metricInterval))),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(mp)
+	return mp, nil
+}
+
+// ShutdownMetrics flushes and closes the meter provider.
+func ShutdownMetrics(ctx context.Context) error {
+	if mp, ok := otel.GetMeterProvider().(*sdkmetric.MeterProvider); ok {
+		return mp.Shutdown(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package attractions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"hotelReservation/snapshot"
+)
+
+// Category is a kind of attraction, such as restaurants, kept in its own
+// collection of attractions-db.
+type Category struct {
+	// Name is how requests select the category, like "restaurant".
+	Name string `json:"name"`
+	// Collection is the collection of attractions-db holding the
+	// attractions, with their coordinates in "lat" and "lon".
+	Collection string `json:"collection"`
+	// IdField and NameField are the fields holding the id and the name of
+	// an attraction.
+	IdField   string `json:"idField"`
+	NameField string `json:"nameField"`
+}
+
+// LoadCategories reads the attraction categories from the JSON file at path,
+// an object with a "categories" array.
+func LoadCategories(path string) ([]Category, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Categories []Category `json:"categories"`
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range file.Categories {
+		if c.Name == "" || c.Collection == "" || c.IdField == "" || c.NameField == "" {
+			return nil, fmt.Errorf("%s: category %q needs a name, collection, idField and nameField", path, c.Name)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("%s: category %q defined twice", path, c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return file.Categories, nil
+}
+
+// category is a Category with the index of its attractions.
+type category struct {
+	Category
+
+	// mu guards index, which is read by queries and changed by the
+	// watcher of the collection, and dirty is set when the index changed
+	// since its last snapshot.
+	mu    sync.RWMutex
+	index *snapshot.Index
+	dirty atomic.Bool
+}
+
+// buildIndex returns the index of the attractions of c in attractions-db.
+func buildIndex(ctx context.Context, client *mongo.Client, c Category) (*snapshot.Index, error) {
+	log.Trace().Msgf("new geo index of %s", c.Collection)
+
+	curr, err := client.Database("attractions-db").Collection(c.Collection).Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var docs []bson.M
+	if err := curr.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	index := snapshot.NewIndex()
+	for _, doc := range docs {
+		p, ok := indexPoint(c, doc)
+		if !ok {
+			log.Warn().Msgf("Skipping %s without %s and coordinates: %v", c.Collection, c.IdField, doc["_id"])
+			continue
+		}
+		index.Add(p)
+	}
+	return index, nil
+}
+
+// indexPoint returns the point of an attraction of c in the index, and
+// false for attractions without an id or coordinates.
+func indexPoint(c Category, doc bson.M) (*snapshot.Point, bool) {
+	id, _ := doc[c.IdField].(string)
+	lat, okLat := doc["lat"].(float64)
+	lon, okLon := doc["lon"].(float64)
+	if id == "" || !okLat || !okLon {
+		return nil, false
+	}
+	return &snapshot.Point{Pid: id, Plat: lat, Plon: lon}, true
+}
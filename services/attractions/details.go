@@ -0,0 +1,134 @@
+package attractions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"hotelReservation/mongoindex"
+	pb "hotelReservation/services/attractions/proto"
+)
+
+const maxPriceLevel = 4
+
+// Days are the days of OpeningHours, from Monday.
+var Days = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// Details describe an attraction besides its id, name and location.
+type Details struct {
+	Address      string         `bson:"address"`
+	OpeningHours []OpeningHours `bson:"openingHours"`
+	// PriceLevel is from 1 (inexpensive) to 4 (very expensive), or 0 when
+	// unknown.
+	PriceLevel int32    `bson:"priceLevel"`
+	Tags       []string `bson:"tags"`
+}
+
+// OpeningHours is a period an attraction is open on a day, with the times
+// as local HH:MM. Periods ending after midnight close before they open.
+type OpeningHours struct {
+	Day   string `bson:"day"`
+	Open  string `bson:"open"`
+	Close string `bson:"close"`
+}
+
+// Place is an attraction to import.
+type Place struct {
+	Id   string
+	Name string
+	Lat  float64
+	Lon  float64
+	Details
+}
+
+// Validate reports the first field of the place that cannot be imported.
+func (p *Place) Validate() error {
+	switch {
+	case p.Id == "":
+		return fmt.Errorf("missing id")
+	case p.Name == "":
+		return fmt.Errorf("%s: missing name", p.Id)
+	case p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180:
+		return fmt.Errorf("%s: coordinates %v, %v out of range", p.Id, p.Lat, p.Lon)
+	case p.PriceLevel < 0 || p.PriceLevel > maxPriceLevel:
+		return fmt.Errorf("%s: price level %d is not between 0 and %d", p.Id, p.PriceLevel, maxPriceLevel)
+	}
+	for _, h := range p.OpeningHours {
+		if !validDay(h.Day) {
+			return fmt.Errorf("%s: unknown day %q", p.Id, h.Day)
+		}
+		for _, t := range []string{h.Open, h.Close} {
+			if _, err := time.Parse("15:04", t); err != nil || len(t) != len("15:04") {
+				return fmt.Errorf("%s: opening time %q is not HH:MM", p.Id, t)
+			}
+		}
+	}
+	return nil
+}
+
+func validDay(day string) bool {
+	for _, d := range Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Import writes the places of category c to its collection, inserting new
+// ones and updating those with the same id. Importing the same places again
+// leaves the collection unchanged. Fields of existing documents that are not
+// imported, like the rating of restaurants, are kept. Places seeded more than
+// once before the collection had a unique id index are deduplicated first.
+func Import(ctx context.Context, client *mongo.Client, c Category, places []Place) (*mongo.BulkWriteResult, error) {
+	collection := client.Database("attractions-db").Collection(c.Collection)
+	err := mongoindex.EnsureUnique(ctx, collection, mongo.IndexModel{
+		Keys:    bson.D{{Key: c.IdField, Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(places) == 0 {
+		return &mongo.BulkWriteResult{}, nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(places))
+	for _, p := range places {
+		hours := p.OpeningHours
+		if hours == nil {
+			hours = []OpeningHours{}
+		}
+		tags := p.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{c.IdField: p.Id}).
+			SetUpdate(bson.M{"$set": bson.M{
+				c.IdField:      p.Id,
+				c.NameField:    p.Name,
+				"lat":          p.Lat,
+				"lon":          p.Lon,
+				"address":      p.Address,
+				"openingHours": hours,
+				"priceLevel":   p.PriceLevel,
+				"tags":         tags,
+			}}).
+			SetUpsert(true))
+	}
+	return collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+}
+
+// addTo copies the details to an attraction returned by the service.
+func (d *Details) addTo(a *pb.Attraction) {
+	a.Address = d.Address
+	a.PriceLevel = d.PriceLevel
+	a.Tags = d.Tags
+	for _, h := range d.OpeningHours {
+		a.OpeningHours = append(a.OpeningHours, &pb.OpeningHours{Day: h.Day, Open: h.Open, Close: h.Close})
+	}
+}
@@ -0,0 +1,189 @@
+package attractions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hailocab/go-geoindex"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "hotelReservation/services/attractions/proto"
+)
+
+type point struct {
+	Pid  string  `bson:"hotelId"`
+	Plat float64 `bson:"lat"`
+	Plon float64 `bson:"lon"`
+}
+
+// Implement Point interface
+func (p *point) Lat() float64 { return p.Plat }
+func (p *point) Lon() float64 { return p.Plon }
+func (p *point) Id() string   { return p.Pid }
+
+// NearbyAttractions returns the attractions of the requested categories
+// within the radius of the hotel, closest first, with their details.
+func (s *Server) NearbyAttractions(ctx context.Context, req *pb.NearbyRequest) (*pb.NearbyResult, error) {
+	log.Trace().Msgf("In Attractions NearbyAttractions")
+
+	radius, limit := req.Radius, int(req.Limit)
+	if radius == 0 {
+		radius = defaultSearchRadius
+	}
+	if limit == 0 {
+		limit = defaultSearchResults
+	}
+	switch {
+	case req.HotelId == "":
+		return nil, status.Error(codes.InvalidArgument, "hotelId is required")
+	case radius < 0 || radius > maxSearchRadius:
+		return nil, status.Errorf(codes.InvalidArgument, "radius must be between 0 and %d km", maxSearchRadius)
+	case limit < 0 || limit > maxSearchResults:
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 0 and %d", maxSearchResults)
+	}
+
+	categories, err := s.selectCategories(req.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_attractions", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	found, err := s.nearby(ctx, req.HotelId, categories, radius, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addDetails(ctx, found); err != nil {
+		log.Error().Msgf("Failed get attraction details: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get attractions")
+	}
+
+	return &pb.NearbyResult{Attractions: found}, nil
+}
+
+// nearby returns the attractions of categories within radius km of the
+// hotel, closest first, from the indexes only: without names and details.
+func (s *Server) nearby(ctx context.Context, hotelId string, categories []*category, radius float64, limit int) ([]*pb.Attraction, error) {
+	var hotel point
+	err := s.MongoClient.Database("attractions-db").Collection("hotels").FindOne(ctx, bson.M{"hotelId": hotelId}).Decode(&hotel)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "hotel %s not found", hotelId)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed get hotel [%v]: %v", hotelId, err)
+		return nil, status.Error(codes.Internal, "failed to get hotel")
+	}
+
+	// the nearest of each category, merged and cut to the limit
+	var found []*pb.Attraction
+	for _, c := range categories {
+		c.mu.RLock()
+		points := c.index.KNearest(&hotel, limit, geoindex.Km(radius), func(p geoindex.Point) bool {
+			return true
+		})
+		c.mu.RUnlock()
+		log.Trace().Msgf("%s after KNearest, len = %d", c.Name, len(points))
+		for _, p := range points {
+			found = append(found, &pb.Attraction{
+				Id:       p.Id(),
+				Category: c.Name,
+				Lat:      p.Lat(),
+				Lon:      p.Lon(),
+				Distance: float64(geoindex.Distance(&hotel, p)) / 1000,
+			})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Distance < found[j].Distance
+	})
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+// selectCategories returns the categories with the given names, or all of
+// them in configuration order when names is empty.
+func (s *Server) selectCategories(names []string) ([]*category, error) {
+	var selected []*category
+	if len(names) == 0 {
+		for _, c := range s.Categories {
+			selected = append(selected, s.categories[c.Name])
+		}
+		return selected, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		c, ok := s.categories[name]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown category %q", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+// addDetails looks up the names and details of the attractions in their
+// collections.
+func (s *Server) addDetails(ctx context.Context, found []*pb.Attraction) error {
+	ids := make(map[string][]string)
+	for _, a := range found {
+		ids[a.Category] = append(ids[a.Category], a.Id)
+	}
+
+	for name, categoryIds := range ids {
+		docs, err := s.findAttractions(ctx, s.categories[name], categoryIds)
+		if err != nil {
+			return err
+		}
+		for _, a := range found {
+			if doc, ok := docs[a.Id]; ok && a.Category == name {
+				a.Name = doc.name
+				doc.addTo(a)
+			}
+		}
+	}
+	return nil
+}
+
+type attraction struct {
+	name string
+	Details
+}
+
+// findAttractions returns the attractions of a category with the given ids
+// by id.
+func (s *Server) findAttractions(ctx context.Context, c *category, ids []string) (map[string]attraction, error) {
+	curr, err := s.MongoClient.Database("attractions-db").Collection(c.Collection).Find(ctx,
+		bson.M{c.IdField: bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{
+			c.IdField: 1, c.NameField: 1, "address": 1, "openingHours": 1, "priceLevel": 1, "tags": 1,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	docs := make(map[string]attraction, len(ids))
+	for curr.Next(ctx) {
+		var a attraction
+		if err := curr.Decode(&a.Details); err != nil {
+			return nil, err
+		}
+		id, _ := curr.Current.Lookup(c.IdField).StringValueOK()
+		a.name, _ = curr.Current.Lookup(c.NameField).StringValueOK()
+		docs[id] = a
+	}
+	return docs, curr.Err()
+}
@@ -19,15 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Attractions_NearbyRest_FullMethodName   = "/attractions.Attractions/NearbyRest"
-	Attractions_NearbyMus_FullMethodName    = "/attractions.Attractions/NearbyMus"
-	Attractions_NearbyCinema_FullMethodName = "/attractions.Attractions/NearbyCinema"
+	Attractions_NearbyAttractions_FullMethodName = "/attractions.Attractions/NearbyAttractions"
+	Attractions_NearbyRest_FullMethodName        = "/attractions.Attractions/NearbyRest"
+	Attractions_NearbyMus_FullMethodName         = "/attractions.Attractions/NearbyMus"
+	Attractions_NearbyCinema_FullMethodName      = "/attractions.Attractions/NearbyCinema"
 )
 
 // AttractionsClient is the client API for Attractions service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AttractionsClient interface {
+	NearbyAttractions(ctx context.Context, in *NearbyRequest, opts ...grpc.CallOption) (*NearbyResult, error)
 	NearbyRest(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
 	NearbyMus(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
 	NearbyCinema(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
@@ -41,6 +43,15 @@ func NewAttractionsClient(cc grpc.ClientConnInterface) AttractionsClient {
 	return &attractionsClient{cc}
 }
 
+func (c *attractionsClient) NearbyAttractions(ctx context.Context, in *NearbyRequest, opts ...grpc.CallOption) (*NearbyResult, error) {
+	out := new(NearbyResult)
+	err := c.cc.Invoke(ctx, Attractions_NearbyAttractions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *attractionsClient) NearbyRest(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error) {
 	out := new(Result)
 	err := c.cc.Invoke(ctx, Attractions_NearbyRest_FullMethodName, in, out, opts...)
@@ -72,6 +83,7 @@ func (c *attractionsClient) NearbyCinema(ctx context.Context, in *Request, opts
 // All implementations must embed UnimplementedAttractionsServer
 // for forward compatibility
 type AttractionsServer interface {
+	NearbyAttractions(context.Context, *NearbyRequest) (*NearbyResult, error)
 	NearbyRest(context.Context, *Request) (*Result, error)
 	NearbyMus(context.Context, *Request) (*Result, error)
 	NearbyCinema(context.Context, *Request) (*Result, error)
@@ -82,6 +94,9 @@ type AttractionsServer interface {
 type UnimplementedAttractionsServer struct {
 }
 
+func (UnimplementedAttractionsServer) NearbyAttractions(context.Context, *NearbyRequest) (*NearbyResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NearbyAttractions not implemented")
+}
 func (UnimplementedAttractionsServer) NearbyRest(context.Context, *Request) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NearbyRest not implemented")
 }
@@ -104,6 +119,24 @@ func RegisterAttractionsServer(s grpc.ServiceRegistrar, srv AttractionsServer) {
 	s.RegisterService(&Attractions_ServiceDesc, srv)
 }
 
+func _Attractions_NearbyAttractions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NearbyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AttractionsServer).NearbyAttractions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Attractions_NearbyAttractions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AttractionsServer).NearbyAttractions(ctx, req.(*NearbyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Attractions_NearbyRest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Request)
 	if err := dec(in); err != nil {
@@ -165,6 +198,10 @@ var Attractions_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "attractions.Attractions",
 	HandlerType: (*AttractionsServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NearbyAttractions",
+			Handler:    _Attractions_NearbyAttractions_Handler,
+		},
 		{
 			MethodName: "NearbyRest",
 			Handler:    _Attractions_NearbyRest_Handler,
@@ -7,35 +7,37 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/hailocab/go-geoindex"
 	"github.com/rs/zerolog/log"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
 	"hotelReservation/registry"
 	pb "hotelReservation/services/attractions/proto"
 	"hotelReservation/tls"
 )
 
 const (
-	name             = "srv-attractions"
-	maxSearchRadius  = 10
-	maxSearchResults = 5
+	name = "srv-attractions"
+
+	// search radius in kilometers
+	defaultSearchRadius  = 10
+	maxSearchRadius      = 50
+	defaultSearchResults = 5
+	maxSearchResults     = 50
 )
 
 // Server implements the attractions service
 type Server struct {
 	pb.UnimplementedAttractionsServer
 
-	indexH *geoindex.ClusteringIndex
-	indexR *geoindex.ClusteringIndex
-	indexM *geoindex.ClusteringIndex
-	indexC *geoindex.ClusteringIndex
-	uuid   string
+	categories map[string]*category
+	uuid       string
 
 	Registry       *registry.Client
 	Tracer         trace.Tracer
@@ -43,6 +45,24 @@ type Server struct {
 	Port           int
 	IpAddr         string
 	MongoClient    *mongo.Client
+	// SnapshotDir is where the indexes are saved and loaded from at
+	// startup. Snapshots are disabled when empty.
+	SnapshotDir string
+	// SnapshotMaxAge is how old a snapshot may be and still be loaded
+	// instead of reading attractions-db. Zero means no limit.
+	SnapshotMaxAge time.Duration
+	// WatchIndexes applies changes made to the collections of the
+	// categories, such as imports, to the indexes while running.
+	WatchIndexes bool
+	// PollInterval is used to reload the indexes when change streams are
+	// unavailable.
+	PollInterval time.Duration
+	// Categories are the kinds of attractions served, each indexed
+	// separately.
+	Categories []Category
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -51,20 +71,19 @@ func (s *Server) Run() error {
 		return fmt.Errorf("server port must be set")
 	}
 
-	if s.indexH == nil {
-		s.indexH = newGeoIndex(s.MongoClient)
-	}
-
-	if s.indexR == nil {
-		s.indexR = newGeoIndexRest(s.MongoClient)
+	if s.categories == nil {
+		s.categories = make(map[string]*category, len(s.Categories))
+		for _, c := range s.Categories {
+			s.categories[c.Name] = &category{Category: c, index: s.loadIndex(c)}
+		}
 	}
 
-	if s.indexM == nil {
-		s.indexM = newGeoIndexMus(s.MongoClient)
+	if s.WatchIndexes {
+		s.watchIndexes(context.Background())
 	}
 
-	if s.indexC == nil {
-		s.indexC = newGeoIndexCinema(s.MongoClient)
+	if s.SnapshotDir != "" {
+		go s.flushSnapshots(context.Background())
 	}
 
 	s.uuid = uuid.New().String()
@@ -95,6 +114,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()), 
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 
 		grpc.ChainStreamInterceptor(
@@ -127,330 +147,48 @@ func (s *Server) Shutdown() {
 	s.Registry.Deregister(s.uuid)
 }
 
-// NearbyRest returns all restaurants close to the hotel.
+// NearbyRest returns the restaurants close to the hotel.
 func (s *Server) NearbyRest(ctx context.Context, req *pb.Request) (*pb.Result, error) {
 	log.Trace().Msgf("In Attractions NearbyRest")
-
-	c := s.MongoClient.Database("attractions-db").Collection("hotels")
-
-	curr, err := c.Find(context.TODO(), bson.M{"hotelId": req.HotelId})
-	if err != nil {
-		log.Error().Msgf("Failed get hotels: ", err)
-	}
-	var hotelReqs []point
-	curr.All(context.TODO(), &hotelReqs)
-
-	var hotelReq point
-
-	for _, hotelHelper := range hotelReqs {
-		hotelReq = hotelHelper
-	}
-
-	var (
-		points = s.getNearbyPointsRest(ctx, float64(hotelReq.Plat), float64(hotelReq.Plon))
-		res    = &pb.Result{}
-	)
-
-	log.Trace().Msgf("restaurants after getNearbyPoints, len = %d", len(points))
-
-	for _, p := range points {
-		log.Trace().Msgf("In restaurants Nearby return restaurantId = %s", p.Id())
-		res.AttractionIds = append(res.AttractionIds, p.Id())
-	}
-
-	return res, nil
+	return s.nearbyIds(ctx, req.HotelId, "restaurant")
 }
 
-// NearbyMus returns all museums close to the hotel.
+// NearbyMus returns the museums close to the hotel.
 func (s *Server) NearbyMus(ctx context.Context, req *pb.Request) (*pb.Result, error) {
 	log.Trace().Msgf("In Attractions NearbyMus")
-
-	c := s.MongoClient.Database("attractions-db").Collection("hotels")
-
-	curr, err := c.Find(context.TODO(), bson.M{"hotelId": req.HotelId})
-	if err != nil {
-		log.Error().Msgf("Failed get hotels: ", err)
-	}
-	var hotelReqs []point
-	curr.All(context.TODO(), &hotelReqs)
-
-	var hotelReq point
-
-	for _, hotelHelper := range hotelReqs {
-		hotelReq = hotelHelper
-	}
-
-	var (
-		points = s.getNearbyPointsMus(ctx, float64(hotelReq.Plat), float64(hotelReq.Plon))
-		res    = &pb.Result{}
-	)
-
-	log.Trace().Msgf("museums after getNearbyPoints, len = %d", len(points))
-
-	for _, p := range points {
-		log.Trace().Msgf("In museums Nearby return museumId = %s", p.Id())
-		res.AttractionIds = append(res.AttractionIds, p.Id())
-	}
-
-	return res, nil
+	return s.nearbyIds(ctx, req.HotelId, "museum")
 }
 
-// NearbyCinema returns all cinemas close to the hotel.
+// NearbyCinema returns the cinemas close to the hotel.
 func (s *Server) NearbyCinema(ctx context.Context, req *pb.Request) (*pb.Result, error) {
 	log.Trace().Msgf("In Attractions NearbyCinema")
-
-	ctx, span := s.Tracer.Start(ctx, "mongo_cinema", trace.WithSpanKind(trace.SpanKindClient))
-	defer span.End()
-
-	c := s.MongoClient.Database("attractions-db").Collection("hotels")
-
-	curr, err := c.Find(context.TODO(), bson.M{"hotelId": req.HotelId})
-	if err != nil {
-		log.Error().Msgf("Failed get hotels: ", err)
-	}
-	var hotelReqs []point
-	curr.All(context.TODO(), &hotelReqs)
-
-	var hotelReq point
-
-	for _, hotelHelper := range hotelReqs {
-		hotelReq = hotelHelper
-	}
-
-	var (
-		points = s.getNearbyPointsCinema(ctx, float64(hotelReq.Plat), float64(hotelReq.Plon))
-		res    = &pb.Result{}
-	)
-
-	log.Trace().Msgf("cinemas after getNearbyPoints, len = %d", len(points))
-
-	for _, p := range points {
-		log.Trace().Msgf("In cinemas Nearby return cinemaId = %s", p.Id())
-		res.AttractionIds = append(res.AttractionIds, p.Id())
-	}
-
-	return res, nil
+	return s.nearbyIds(ctx, req.HotelId, "cinema")
 }
 
-func (s *Server) getNearbyPointsHotel(ctx context.Context, lat, lon float64) []geoindex.Point {
-	log.Trace().Msgf("In geo getNearbyPoints, lat = %f, lon = %f", lat, lon)
-
-	center := &geoindex.GeoPoint{
-		Pid:  "",
-		Plat: lat,
-		Plon: lon,
+// nearbyIds returns the ids of the attractions of a category close to the
+// hotel, with the default radius and limit. The category must be configured
+// under the name the RPC was made for.
+func (s *Server) nearbyIds(ctx context.Context, hotelId, name string) (*pb.Result, error) {
+	c, ok := s.categories[name]
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "attraction category %q is not configured", name)
 	}
-
-	return s.indexH.KNearest(
-		center,
-		maxSearchResults,
-		geoindex.Km(maxSearchRadius), func(p geoindex.Point) bool {
-			return true
-		},
-	)
-}
-
-func (s *Server) getNearbyPointsRest(ctx context.Context, lat, lon float64) []geoindex.Point {
-	log.Trace().Msgf("In geo getNearbyPointsRest, lat = %f, lon = %f", lat, lon)
-
-	center := &geoindex.GeoPoint{
-		Pid:  "",
-		Plat: lat,
-		Plon: lon,
+	if hotelId == "" {
+		return nil, status.Error(codes.InvalidArgument, "hotelId is required")
 	}
 
-	return s.indexR.KNearest(
-		center,
-		maxSearchResults,
-		geoindex.Km(maxSearchRadius), func(p geoindex.Point) bool {
-			return true
-		},
-	)
-}
-
-func (s *Server) getNearbyPointsMus(ctx context.Context, lat, lon float64) []geoindex.Point {
-	log.Trace().Msgf("In geo getNearbyPointsMus, lat = %f, lon = %f", lat, lon)
-
-	center := &geoindex.GeoPoint{
-		Pid:  "",
-		Plat: lat,
-		Plon: lon,
-	}
-
-	return s.indexM.KNearest(
-		center,
-		maxSearchResults,
-		geoindex.Km(maxSearchRadius), func(p geoindex.Point) bool {
-			return true
-		},
-	)
-}
-
-func (s *Server) getNearbyPointsCinema(ctx context.Context, lat, lon float64) []geoindex.Point {
-	log.Trace().Msgf("In geo getNearbyPointsCinema, lat = %f, lon = %f", lat, lon)
-
-	center := &geoindex.GeoPoint{
-		Pid:  "",
-		Plat: lat,
-		Plon: lon,
-	}
-
-	return s.indexC.KNearest(
-		center,
-		maxSearchResults,
-		geoindex.Km(maxSearchRadius), func(p geoindex.Point) bool {
-			return true
-		},
-	)
-}
-
-// newGeoIndex returns a geo index with points loaded
-func newGeoIndex(client *mongo.Client) *geoindex.ClusteringIndex {
-	log.Trace().Msg("new geo newGeoIndex")
-
-	collection := client.Database("attractions-db").Collection("hotels")
-	curr, err := collection.Find(context.TODO(), bson.D{})
-	if err != nil {
-		log.Error().Msgf("Failed get hotels data: ", err)
-	}
-
-	var points []*point
-	curr.All(context.TODO(), &points)
-	if err != nil {
-		log.Error().Msgf("Failed get hotels data: ", err)
-	}
-
-	// add points to index
-	index := geoindex.NewClusteringIndex()
-	for _, point := range points {
-		index.Add(point)
-	}
-
-	return index
-}
-
-// newGeoIndexRest returns a geo index with points loaded
-func newGeoIndexRest(client *mongo.Client) *geoindex.ClusteringIndex {
-	log.Trace().Msg("new geo newGeoIndexRest")
-
-	collection := client.Database("attractions-db").Collection("restaurants")
-	curr, err := collection.Find(context.TODO(), bson.D{})
-	if err != nil {
-		log.Error().Msgf("Failed get restaurant data: ", err)
-	}
-
-	var points []*Restaurant
-	curr.All(context.TODO(), &points)
-	if err != nil {
-		log.Error().Msgf("Failed get restaurant data: ", err)
-	}
-
-	// add points to index
-	index := geoindex.NewClusteringIndex()
-	for _, point := range points {
-		index.Add(point)
-	}
-
-	return index
-}
-
-// newGeoIndexMus returns a geo index with points loaded
-func newGeoIndexMus(client *mongo.Client) *geoindex.ClusteringIndex {
-	log.Trace().Msg("new geo newGeoIndexMus")
-
-	collection := client.Database("attractions-db").Collection("museums")
-	curr, err := collection.Find(context.TODO(), bson.D{})
-	if err != nil {
-		log.Error().Msgf("Failed get restaurant data: ", err)
-	}
-
-	var points []*Museum
-	curr.All(context.TODO(), &points)
-	if err != nil {
-		log.Error().Msgf("Failed get restaurant data: ", err)
-	}
-
-	// add points to index
-	index := geoindex.NewClusteringIndex()
-	for _, point := range points {
-		index.Add(point)
-	}
-
-	return index
-}
-
-// newGeoIndexCinema returns a geo index with points loaded
-func newGeoIndexCinema(client *mongo.Client) *geoindex.ClusteringIndex {
-	log.Trace().Msg("new geo newGeoIndexCinema")
-
-	collection := client.Database("attractions-db").Collection("cinemas")
-	curr, err := collection.Find(context.TODO(), bson.D{})
-	if err != nil {
-		log.Error().Msgf("Failed get cinema data: ", err)
-	}
+	ctx, span := s.Tracer.Start(ctx, "mongo_attractions", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
 
-	var points []*Cinema
-	curr.All(context.TODO(), &points)
+	found, err := s.nearby(ctx, hotelId, []*category{c}, defaultSearchRadius, defaultSearchResults)
 	if err != nil {
-		log.Error().Msgf("Failed get cinema data: ", err)
+		return nil, err
 	}
 
-	// add points to index
-	index := geoindex.NewClusteringIndex()
-	for _, point := range points {
-		index.Add(point)
+	res := &pb.Result{}
+	for _, a := range found {
+		log.Trace().Msgf("In %s Nearby return id = %s", name, a.Id)
+		res.AttractionIds = append(res.AttractionIds, a.Id)
 	}
-
-	return index
-}
-
-type point struct {
-	Pid  string  `bson:"hotelId"`
-	Plat float64 `bson:"lat"`
-	Plon float64 `bson:"lon"`
-}
-
-// Implement Point interface
-func (p *point) Lat() float64 { return p.Plat }
-func (p *point) Lon() float64 { return p.Plon }
-func (p *point) Id() string   { return p.Pid }
-
-type Restaurant struct {
-	RestaurantId   string  `bson:"restaurantId"`
-	RLat           float64 `bson:"lat"`
-	RLon           float64 `bson:"lon"`
-	RestaurantName string  `bson:"restaurantName"`
-	Rating         float32 `bson:"rating"`
-	Type           string  `bson:"type"`
-}
-
-// Implement Restaurant interface
-func (r *Restaurant) Lat() float64 { return r.RLat }
-func (r *Restaurant) Lon() float64 { return r.RLon }
-func (r *Restaurant) Id() string   { return r.RestaurantId }
-
-type Museum struct {
-	MuseumId   string  `bson:"museumId"`
-	MLat       float64 `bson:"lat"`
-	MLon       float64 `bson:"lon"`
-	MuseumName string  `bson:"museumName"`
-	Type       string  `bson:"type"`
-}
-
-// Implement Museum interface
-func (m *Museum) Lat() float64 { return m.MLat }
-func (m *Museum) Lon() float64 { return m.MLon }
-func (m *Museum) Id() string   { return m.MuseumId }
-
-type Cinema struct {
-	CinemaId   string  `bson:"cinemaId"`
-	CLat       float64 `bson:"lat"`
-	CLon       float64 `bson:"lon"`
-	CinemaName string  `bson:"cinemaName"`
-	Type       string  `bson:"type"`
+	return res, nil
 }
-
-// Implement Cinema interface
-func (c *Cinema) Lat() float64 { return c.CLat }
-func (c *Cinema) Lon() float64 { return c.CLon }
-func (c *Cinema) Id() string   { return c.CinemaId }
@@ -0,0 +1,105 @@
+package attractions
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"hotelReservation/snapshot"
+)
+
+// snapshotFlushInterval is how often changed indexes are written to their
+// snapshots. Changes only mark an index as changed, so that imports do not
+// write the whole index for every attraction.
+const snapshotFlushInterval = 10 * time.Second
+
+func (s *Server) snapshotPath(c Category) string {
+	return filepath.Join(s.SnapshotDir, c.Collection+".snap")
+}
+
+// loadIndex returns the index of the attractions of c stored in its
+// snapshot, or builds it from attractions-db and saves a new snapshot when
+// that one is missing, stale or does not hold the attractions of c.
+func (s *Server) loadIndex(c Category) *snapshot.Index {
+	if s.SnapshotDir != "" {
+		start := time.Now()
+		index, err := snapshot.Load(s.snapshotPath(c), s.SnapshotMaxAge)
+		if err == nil {
+			err = s.checkSnapshot(context.TODO(), c, index)
+		}
+		if err == nil {
+			log.Info().Msgf("Loaded %s index snapshot %s with %d points in %v", c.Collection, s.snapshotPath(c), index.Len(), time.Since(start))
+			return index
+		}
+		log.Info().Msgf("%s index snapshot not loaded, reading attractions-db: %v", c.Collection, err)
+	}
+
+	index, err := buildIndex(context.TODO(), s.MongoClient, c)
+	if err != nil {
+		log.Error().Msgf("Failed get %s data: %v", c.Collection, err)
+		return snapshot.NewIndex()
+	}
+	if s.SnapshotDir != "" {
+		if err := index.Save(s.snapshotPath(c)); err != nil {
+			log.Error().Msgf("Failed to save %s index snapshot %s: %v", c.Collection, s.snapshotPath(c), err)
+		}
+	}
+
+	return index
+}
+
+// checkSnapshot returns an error unless index holds the attractions of c
+// with coordinates, which may have changed while the service was down.
+func (s *Server) checkSnapshot(ctx context.Context, c Category, index *snapshot.Index) error {
+	collection := s.MongoClient.Database("attractions-db").Collection(c.Collection)
+	values, err := collection.Distinct(ctx, c.IdField, bson.M{
+		"lat": bson.M{"$type": "double"},
+		"lon": bson.M{"$type": "double"},
+	})
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return index.Check(ids)
+}
+
+// flushSnapshots writes the changed indexes every snapshotFlushInterval
+// until ctx is done.
+func (s *Server) flushSnapshots(ctx context.Context) {
+	ticker := time.NewTicker(snapshotFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, c := range s.categories {
+				s.flushSnapshot(c)
+			}
+		}
+	}
+}
+
+// flushSnapshot writes the index of c to its snapshot if it changed since
+// the last snapshot.
+func (s *Server) flushSnapshot(c *category) {
+	if !c.dirty.Swap(false) {
+		return
+	}
+
+	c.mu.RLock()
+	err := c.index.Save(s.snapshotPath(c.Category))
+	c.mu.RUnlock()
+	if err != nil {
+		log.Error().Msgf("Failed to save %s index snapshot %s: %v", c.Collection, s.snapshotPath(c.Category), err)
+		c.dirty.Store(true)
+	}
+}
@@ -0,0 +1,63 @@
+package attractions
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"hotelReservation/mongowatch"
+)
+
+// watchIndexes keeps the index of each category in sync with its
+// collection, such as after imports.
+func (s *Server) watchIndexes(ctx context.Context) {
+	for _, c := range s.categories {
+		w := &mongowatch.Watcher{
+			Collection:   s.MongoClient.Database("attractions-db").Collection(c.Collection),
+			PollInterval: s.PollInterval,
+			Apply:        c.applyChange,
+			Reload: func(ctx context.Context) error {
+				return c.reloadIndex(ctx, s.MongoClient)
+			},
+		}
+		go w.Run(ctx)
+	}
+}
+
+// applyChange adds an inserted or updated attraction to the index, or
+// removes it when it has no coordinates anymore.
+func (c *category) applyChange(_ context.Context, doc bson.Raw) error {
+	var m bson.M
+	if err := bson.Unmarshal(doc, &m); err != nil {
+		return err
+	}
+	p, ok := indexPoint(c.Category, m)
+	log.Trace().Msgf("%s change, %s = %v", c.Collection, c.IdField, m[c.IdField])
+
+	c.mu.Lock()
+	if ok {
+		c.index.Add(p)
+	} else if id, _ := m[c.IdField].(string); id != "" {
+		c.index.Remove(id)
+	}
+	c.mu.Unlock()
+	c.dirty.Store(true)
+	return nil
+}
+
+// reloadIndex rebuilds the index from MongoDB and swaps it in.
+func (c *category) reloadIndex(ctx context.Context, client *mongo.Client) error {
+	index, err := buildIndex(ctx, client, c.Category)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.index = index
+	c.mu.Unlock()
+	c.dirty.Store(true)
+
+	log.Trace().Msgf("%s index reloaded, points = %d", c.Collection, index.Len())
+	return nil
+}
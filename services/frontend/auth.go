@@ -0,0 +1,228 @@
+package frontend
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	user "hotelReservation/services/user/proto"
+)
+
+const (
+	// keyRefreshInterval is how often the token keys are fetched from the
+	// user service.
+	keyRefreshInterval = time.Minute
+	// minKeyRefreshInterval limits the fetches caused by tokens signed
+	// with a key that is not known yet.
+	minKeyRefreshInterval = 10 * time.Second
+)
+
+// tokenKeys is the key set session tokens are checked against, a copy of
+// the one published by the user service.
+type tokenKeys struct {
+	mu      sync.RWMutex
+	keys    auth.Keys
+	fetched time.Time
+}
+
+func (k *tokenKeys) Key(kid string) (crypto.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// refreshKeys replaces the token keys with the user service's key set.
+func (s *Server) refreshKeys(ctx context.Context) error {
+	s.tokenKeys.mu.Lock()
+	s.tokenKeys.fetched = time.Now()
+	s.tokenKeys.mu.Unlock()
+
+	res, err := s.userClient.GetKeys(ctx, &user.KeysRequest{})
+	if err != nil {
+		return err
+	}
+
+	keys := make(auth.Keys, len(res.Keys))
+	for _, key := range res.Keys {
+		keys[key.Kid] = ed25519.PublicKey(key.PublicKey)
+	}
+
+	s.tokenKeys.mu.Lock()
+	s.tokenKeys.keys = keys
+	s.tokenKeys.mu.Unlock()
+	return nil
+}
+
+// refreshKeysLoop fetches the token keys every keyRefreshInterval.
+func (s *Server) refreshKeysLoop(ctx context.Context) {
+	ticker := time.NewTicker(keyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.refreshKeys(ctx); err != nil {
+				log.Error().Msgf("Failed to refresh token keys: %v", err)
+			}
+		}
+	}
+}
+
+// verifyToken checks a session token locally. A token signed with a key
+// that is not known yet, e.g. right after a rotation, triggers a key
+// refresh, at most once every minKeyRefreshInterval.
+func (s *Server) verifyToken(ctx context.Context, token string) (*auth.Claims, error) {
+	claims, err := auth.Verify(token, &s.tokenKeys, time.Now())
+	if !errors.Is(err, auth.ErrUnknownKey) {
+		return claims, err
+	}
+
+	s.tokenKeys.mu.RLock()
+	recent := time.Since(s.tokenKeys.fetched) < minKeyRefreshInterval
+	s.tokenKeys.mu.RUnlock()
+	if recent {
+		return nil, err
+	}
+	if err := s.refreshKeys(ctx); err != nil {
+		log.Error().Msgf("Failed to refresh token keys: %v", err)
+	}
+	return auth.Verify(token, &s.tokenKeys, time.Now())
+}
+
+// authenticated only passes requests with a valid session token in an
+// "Authorization: Bearer" header on to next, with the identity of the user
+// in the request context.
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		token, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="hotelreservation"`)
+			http.Error(w, "Please log in", http.StatusUnauthorized)
+			return
+		}
+		claims, err := s.verifyToken(r.Context(), token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="hotelreservation", error="invalid_token"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r.WithContext(auth.WithIdentity(r.Context(), claims.Identity())))
+	}
+}
+
+// optionallyAuthenticated is authenticated for routes that also serve
+// anonymous users: requests without a session token are passed on to next
+// without an identity, requests with an invalid one are still rejected.
+func (s *Server) optionallyAuthenticated(next http.HandlerFunc) http.HandlerFunc {
+	withToken := s.authenticated(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := bearerToken(r); !ok {
+			next(w, r)
+			return
+		}
+		withToken(w, r)
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if len(h) < len("Bearer ") || !strings.EqualFold(h[:len("Bearer ")], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(h[len("Bearer "):])
+	return token, token != ""
+}
+
+// clientIP returns the address of the client of a request, for the user
+// service to throttle failed logins by. Forwarding headers are ignored, a
+// client could set them to anything.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (s *Server) loginHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := auth.WithClientIP(r.Context(), clientIP(r))
+	username, password := r.PostFormValue("username"), r.PostFormValue("password")
+	if username == "" || password == "" {
+		http.Error(w, "Please specify username and password", http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.userClient.Login(ctx, &user.Request{
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	writeSessionToken(w, res)
+}
+
+func writeSessionToken(w http.ResponseWriter, res *user.LoginResult) {
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":      res.Token,
+		"token_type": "Bearer",
+		"expires_at": res.ExpiresAt,
+	})
+}
+
+// roleHandler changes the role of a user. The user service only allows
+// admins to.
+func (s *Server) roleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	username, role := r.PostFormValue("username"), r.PostFormValue("role")
+	if username == "" || role == "" {
+		http.Error(w, "Please specify username and role", http.StatusBadRequest)
+		return
+	}
+	var hotels []string
+	if h := r.PostFormValue("hotels"); h != "" {
+		hotels = strings.Split(h, ",")
+	}
+
+	_, err := s.userClient.SetRole(ctx, &user.SetRoleRequest{
+		Username: username,
+		Role:     role,
+		Hotels:   hotels,
+	})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Role changed successfully!",
+	})
+}
@@ -0,0 +1,419 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	"hotelReservation/blobstore"
+	profile "hotelReservation/services/profile/proto"
+	review "hotelReservation/services/review/proto"
+)
+
+const (
+	// maxImagesPerUpload is the most images of an upload request.
+	maxImagesPerUpload = 5
+	// maxImagePixels bounds the memory taken to decode an image, at most 8
+	// bytes a pixel for PNGs with 16 bit channels.
+	maxImagePixels = 16 << 20
+	// thumbnailSize is the longest side of thumbnails, in pixels.
+	thumbnailSize = 320
+)
+
+// imageTypes are the content types of the images users may upload, with
+// the extensions they are stored with.
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// decodeSlot bounds the images decoded at a time to the CPUs decoding can
+// use, so that concurrent uploads do not add up the memory taken by
+// decoding beyond that.
+var decodeSlot = make(chan struct{}, runtime.GOMAXPROCS(0))
+
+// storedImage is an uploaded image in the image store.
+type storedImage struct {
+	Id           string `json:"id"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Width        int32  `json:"width"`
+	Height       int32  `json:"height"`
+	Size         int64  `json:"size"`
+
+	keys []string
+}
+
+// uploadError is a rejected upload, reported with its HTTP status.
+type uploadError struct {
+	status int
+	msg    string
+}
+
+func (e *uploadError) Error() string { return e.msg }
+
+// reviewImagesHandler adds the images of a multipart form to a review of the
+// user: reviewId and up to maxImagesPerUpload image files.
+func (s *Server) reviewImagesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	files, ok := s.parseImageUpload(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	reviewId := r.FormValue("reviewId")
+	if !validImageOwner(reviewId) {
+		http.Error(w, "Please specify a valid reviewId", http.StatusBadRequest)
+		return
+	}
+	// the review service checks this again when the images are added, but
+	// before decoding saves the work for uploads it would reject
+	check := &review.AddImagesRequest{ReviewId: reviewId, ValidateOnly: true}
+	for range files {
+		check.Images = append(check.Images, &review.Image{})
+	}
+	if _, err := s.reviewClient.AddImages(ctx, check); err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	images, err := s.storeImages(ctx, "reviews/"+reviewId+"/", files)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	req := &review.AddImagesRequest{ReviewId: reviewId}
+	for _, img := range images {
+		req.Images = append(req.Images, &review.Image{
+			Id:           img.Id,
+			Url:          img.Url,
+			ThumbnailUrl: img.ThumbnailUrl,
+			ContentType:  img.ContentType,
+			Width:        img.Width,
+			Height:       img.Height,
+			Size:         img.Size,
+		})
+	}
+	if _, err := s.reviewClient.AddImages(ctx, req); err != nil {
+		s.deleteImages(ctx, images)
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": fmt.Sprintf("Added %d images to review %s.", len(images), reviewId),
+		"images":  images,
+	})
+}
+
+// hotelImagesHandler adds the images of a multipart form to a hotel
+// profile: hotelId and up to maxImagesPerUpload image files.
+func (s *Server) hotelImagesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	files, ok := s.parseImageUpload(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	hotelId := r.FormValue("hotelId")
+	if !validImageOwner(hotelId) {
+		http.Error(w, "Please specify a valid hotelId", http.StatusBadRequest)
+		return
+	}
+	// the profile service checks this again, but before decoding saves the
+	// work for uploads it would reject
+	if id := auth.FromContext(ctx); !id.Manages(hotelId) && id.Role != auth.RoleAdmin {
+		http.Error(w, fmt.Sprintf("Only managers of hotel %s may add images to it", hotelId), http.StatusForbidden)
+		return
+	}
+
+	images, err := s.storeImages(ctx, "hotels/"+hotelId+"/", files)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	req := &profile.AddImagesRequest{HotelId: hotelId}
+	for _, img := range images {
+		req.Images = append(req.Images, &profile.Image{
+			Id:           img.Id,
+			Url:          img.Url,
+			ThumbnailUrl: img.ThumbnailUrl,
+			ContentType:  img.ContentType,
+			Width:        img.Width,
+			Height:       img.Height,
+			Size:         img.Size,
+		})
+	}
+	if _, err := s.profileClient.AddImages(ctx, req); err != nil {
+		s.deleteImages(ctx, images)
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": fmt.Sprintf("Added %d images to hotel %s.", len(images), hotelId),
+		"images":  images,
+	})
+}
+
+// imageHandler serves the objects of the image store under /images/.
+func (s *Server) imageHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	key := strings.TrimPrefix(r.URL.Path, "/images/")
+	contentType := ""
+	for t, ext := range imageTypes {
+		if path.Ext(key) == ext {
+			contentType = t
+		}
+	}
+	if contentType == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	obj, err := s.Images.Get(r.Context(), key)
+	if errors.Is(err, blobstore.ErrNotFound) || errors.Is(err, blobstore.ErrInvalidKey) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to read image [%v]: %v", key, err)
+		http.Error(w, "Failed to read image", http.StatusInternalServerError)
+		return
+	}
+	defer obj.Close()
+
+	// keys are never reused for other images
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	io.Copy(w, obj)
+}
+
+// parseImageUpload reads a multipart upload of images, reporting requests
+// that are too large or have no images.
+func (s *Server) parseImageUpload(w http.ResponseWriter, r *http.Request) ([]*multipart.FileHeader, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImagesPerUpload*s.MaxImageBytes+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Upload is too large", http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, "Please upload images as multipart/form-data", http.StatusBadRequest)
+		}
+		return nil, false
+	}
+
+	files := r.MultipartForm.File["image"]
+	if len(files) == 0 || len(files) > maxImagesPerUpload {
+		http.Error(w, fmt.Sprintf("Please upload 1 to %d image files", maxImagesPerUpload), http.StatusBadRequest)
+		return nil, false
+	}
+	return files, true
+}
+
+// storeImages checks uploaded images and stores them with their thumbnails
+// under prefix. Nothing is stored if any image is rejected.
+func (s *Server) storeImages(ctx context.Context, prefix string, files []*multipart.FileHeader) ([]storedImage, error) {
+	images := make([]storedImage, 0, len(files))
+	for _, fh := range files {
+		img, err := s.storeImage(ctx, prefix, fh)
+		if err != nil {
+			s.deleteImages(ctx, images)
+			return nil, err
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+func (s *Server) storeImage(ctx context.Context, prefix string, fh *multipart.FileHeader) (storedImage, error) {
+	var img storedImage
+	if fh.Size > s.MaxImageBytes {
+		return img, &uploadError{http.StatusRequestEntityTooLarge, fmt.Sprintf("%s is larger than %d bytes", fh.Filename, s.MaxImageBytes)}
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return img, err
+	}
+	data, err := io.ReadAll(io.LimitReader(f, s.MaxImageBytes+1))
+	f.Close()
+	if err != nil {
+		return img, err
+	}
+	if int64(len(data)) > s.MaxImageBytes {
+		return img, &uploadError{http.StatusRequestEntityTooLarge, fmt.Sprintf("%s is larger than %d bytes", fh.Filename, s.MaxImageBytes)}
+	}
+
+	// the declared type must be an image type and agree with the content
+	contentType := http.DetectContentType(data)
+	ext, ok := imageTypes[contentType]
+	declared, _, _ := mime.ParseMediaType(fh.Header.Get("Content-Type"))
+	if !ok {
+		return img, &uploadError{http.StatusUnsupportedMediaType, fmt.Sprintf("%s is not a JPEG, PNG or GIF image", fh.Filename)}
+	}
+	if declared != contentType {
+		return img, &uploadError{http.StatusUnsupportedMediaType, fmt.Sprintf("%s is sent as %q but is %s", fh.Filename, declared, contentType)}
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return img, &uploadError{http.StatusBadRequest, fmt.Sprintf("%s is not a valid image", fh.Filename)}
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return img, &uploadError{http.StatusRequestEntityTooLarge, fmt.Sprintf("%s has more than %d pixels", fh.Filename, maxImagePixels)}
+	}
+	thumb, thumbExt, err := makeThumbnail(ctx, data, contentType)
+	if err == errInvalidImage {
+		return img, &uploadError{http.StatusBadRequest, fmt.Sprintf("%s is not a valid image", fh.Filename)}
+	}
+	if err != nil {
+		return img, err
+	}
+
+	img = storedImage{
+		Id:          uuid.New().String(),
+		ContentType: contentType,
+		Width:       int32(config.Width),
+		Height:      int32(config.Height),
+		Size:        int64(len(data)),
+	}
+	key, thumbKey := prefix+img.Id+ext, prefix+img.Id+"_thumb"+thumbExt
+	if err := s.Images.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return img, err
+	}
+	img.keys = append(img.keys, key)
+	if err := s.Images.Put(ctx, thumbKey, thumb); err != nil {
+		s.deleteImages(ctx, []storedImage{img})
+		return img, err
+	}
+	img.keys = append(img.keys, thumbKey)
+	img.Url, img.ThumbnailUrl = "/images/"+key, "/images/"+thumbKey
+
+	return img, nil
+}
+
+var errInvalidImage = errors.New("invalid image")
+
+// makeThumbnail decodes an image, waiting for decodeSlot, and returns its
+// thumbnail encoded as a JPEG for JPEG images and as a PNG otherwise, with
+// its extension.
+func makeThumbnail(ctx context.Context, data []byte, contentType string) (*bytes.Buffer, string, error) {
+	select {
+	case decodeSlot <- struct{}{}:
+		defer func() { <-decodeSlot }()
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", errInvalidImage
+	}
+
+	thumb := new(bytes.Buffer)
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(thumb, thumbnail(decoded), &jpeg.Options{Quality: 80})
+		return thumb, ".jpg", err
+	}
+	err = png.Encode(thumb, thumbnail(decoded))
+	return thumb, ".png", err
+}
+
+// deleteImages removes stored images again, for uploads that failed.
+func (s *Server) deleteImages(ctx context.Context, images []storedImage) {
+	for _, img := range images {
+		for _, key := range img.keys {
+			if err := s.Images.Delete(ctx, key); err != nil {
+				log.Error().Msgf("Failed to delete image [%v]: %v", key, err)
+			}
+		}
+	}
+}
+
+func writeUploadError(w http.ResponseWriter, err error) {
+	var uerr *uploadError
+	if errors.As(err, &uerr) {
+		http.Error(w, uerr.msg, uerr.status)
+		return
+	}
+	log.Error().Msgf("Failed to store images: %v", err)
+	http.Error(w, "Failed to store images", http.StatusInternalServerError)
+}
+
+// validImageOwner reports whether id, of a review or hotel, can be part of
+// the keys of its images.
+func validImageOwner(id string) bool {
+	return !strings.Contains(id, "/") && blobstore.ValidKey(id)
+}
+
+// thumbnail scales img down to fit in thumbnailSize pixels, each pixel the
+// average of the pixels it covers. Smaller images keep their size. The rows
+// covered by each thumbnail row are converted to RGBA with image/draw, which
+// has fast paths for the image types the decoders return.
+func thumbnail(img image.Image) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > thumbnailSize && w >= h {
+		tw, th = thumbnailSize, h*thumbnailSize/w
+	} else if h > thumbnailSize {
+		tw, th = w*thumbnailSize/h, thumbnailSize
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	strip := image.NewRGBA(image.Rect(0, 0, w, (h+th-1)/th))
+	for y := 0; y < th; y++ {
+		y0, y1 := y*h/th, (y+1)*h/th
+		draw.Draw(strip, image.Rect(0, 0, w, y1-y0), img, image.Pt(b.Min.X, b.Min.Y+y0), draw.Src)
+		for x := 0; x < tw; x++ {
+			x0, x1 := x*w/tw, (x+1)*w/tw
+			var sum [4]int
+			for sy := 0; sy < y1-y0; sy++ {
+				row := strip.Pix[sy*strip.Stride+x0*4 : sy*strip.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0], sum[1], sum[2], sum[3] = sum[0]+int(row[i]), sum[1]+int(row[i+1]), sum[2]+int(row[i+2]), sum[3]+int(row[i+3])
+				}
+			}
+			n := (x1 - x0) * (y1 - y0)
+			px := dst.Pix[y*dst.Stride+x*4:]
+			px[0], px[1], px[2], px[3] = uint8(sum[0]/n), uint8(sum[1]/n), uint8(sum[2]/n), uint8(sum[3]/n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,114 @@
+package frontend
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth/oidc"
+	user "hotelReservation/services/user/proto"
+)
+
+// oidcFlowCookie keeps the state, nonce and PKCE verifier of a login in the
+// browser between /oidc/login and /oidc/callback, so any replica can finish
+// the flow.
+const (
+	oidcFlowCookie = "oidc_flow"
+	oidcFlowMaxAge = 600
+)
+
+func (s *Server) oidc(ctx context.Context) (*oidc.Provider, error) {
+	return s.oidcProvider.Get(ctx, s.OIDC, nil)
+}
+
+// oidcLoginHandler sends the user to the issuer to log in.
+func (s *Server) oidcLoginHandler(w http.ResponseWriter, r *http.Request) {
+	if s.OIDC.Issuer == "" {
+		http.Error(w, "OpenID Connect login is not configured", http.StatusNotFound)
+		return
+	}
+	p, err := s.oidc(r.Context())
+	if err != nil {
+		log.Error().Msgf("Failed to discover OpenID Connect issuer: %v", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	flow, err := oidc.NewFlow()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	b, _ := json.Marshal(flow)
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcFlowCookie,
+		Value:    base64.RawURLEncoding.EncodeToString(b),
+		Path:     "/oidc/",
+		MaxAge:   oidcFlowMaxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, p.AuthCodeURL(flow), http.StatusFound)
+}
+
+// oidcCallbackHandler finishes a login: it redeems the code, verifies the
+// ID token and returns a session token like /login.
+func (s *Server) oidcCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	if e := q.Get("error"); e != "" {
+		http.Error(w, "Login failed: "+e+" "+q.Get("error_description"), http.StatusUnauthorized)
+		return
+	}
+
+	var flow oidc.Flow
+	c, err := r.Cookie(oidcFlowCookie)
+	if err == nil {
+		var b []byte
+		if b, err = base64.RawURLEncoding.DecodeString(c.Value); err == nil {
+			err = json.Unmarshal(b, &flow)
+		}
+	}
+	if err != nil || !flow.CheckState(q.Get("state")) {
+		http.Error(w, "Login expired or started elsewhere, please try again", http.StatusBadRequest)
+		return
+	}
+	// the flow is used once
+	http.SetCookie(w, &http.Cookie{Name: oidcFlowCookie, Path: "/oidc/", MaxAge: -1})
+
+	code := q.Get("code")
+	if code == "" {
+		http.Error(w, "Please specify code params", http.StatusBadRequest)
+		return
+	}
+	p, err := s.oidc(ctx)
+	if err != nil {
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+	token, err := p.Exchange(ctx, code, flow)
+	if err != nil {
+		log.Warn().Msgf("OpenID Connect login failed: %v", err)
+		http.Error(w, "Login failed", http.StatusUnauthorized)
+		return
+	}
+
+	// the user service verifies the token again rather than trusting us
+	res, err := s.userClient.ExternalLogin(ctx, &user.ExternalLoginRequest{
+		IdToken: token.Raw,
+		Nonce:   flow.Nonce,
+	})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	writeSessionToken(w, res)
+}
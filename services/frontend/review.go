@@ -0,0 +1,207 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/status"
+	review "hotelReservation/services/review/proto"
+)
+
+// submitReviewHandler submits a review of a hotel the user stayed at. It
+// is published once a moderator approves it.
+func (s *Server) submitReviewHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	hotelId, description := r.PostFormValue("hotelId"), r.PostFormValue("description")
+	if hotelId == "" || description == "" {
+		http.Error(w, "Please specify hotelId, rating and description", http.StatusBadRequest)
+		return
+	}
+	// ParseFloat accepts "NaN" and "Inf"
+	rating, err := strconv.ParseFloat(r.PostFormValue("rating"), 32)
+	if err != nil || math.IsNaN(rating) || math.IsInf(rating, 0) {
+		http.Error(w, "Please specify a numeric rating", http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.reviewClient.SubmitReview(ctx, &review.SubmitRequest{
+		HotelId:     hotelId,
+		Rating:      float32(rating),
+		Description: description,
+	})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Review submitted, it will be published once approved.",
+		"review":  res,
+	})
+}
+
+// moderationQueueHandler lists the reviews waiting for moderation, or in
+// the status of the status parameter.
+func (s *Server) moderationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	limit := 0
+	if l := q.Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil {
+			http.Error(w, "Please specify a numeric limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	res, err := s.reviewClient.GetModerationQueue(ctx, &review.QueueRequest{
+		Status:  q.Get("status"),
+		HotelId: q.Get("hotelId"),
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"reviews": res.Reviews,
+	})
+}
+
+// moderateReviewHandler approves or rejects a review.
+func (s *Server) moderateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	reviewId, st := r.PostFormValue("reviewId"), r.PostFormValue("status")
+	if reviewId == "" || st == "" {
+		http.Error(w, "Please specify reviewId and status", http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.reviewClient.ModerateReview(ctx, &review.ModerateRequest{
+		ReviewId: reviewId,
+		Status:   st,
+		Note:     r.PostFormValue("note"),
+	})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Review " + res.Status + ".",
+		"review":  res,
+	})
+}
+
+// helpfulHandler votes a review helpful, or withdraws the vote with
+// helpful=false.
+func (s *Server) helpfulHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	reviewId := r.PostFormValue("reviewId")
+	if reviewId == "" {
+		http.Error(w, "Please specify reviewId", http.StatusBadRequest)
+		return
+	}
+	helpful := true
+	if v := r.PostFormValue("helpful"); v != "" {
+		var err error
+		if helpful, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "helpful must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	res, err := s.reviewClient.VoteHelpful(ctx, &review.VoteRequest{ReviewId: reviewId, Helpful: helpful})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Review voted by " + strconv.FormatInt(res.HelpfulCount, 10) + " users as helpful.",
+		"review":  res,
+	})
+}
+
+// replyHandler posts the reply of a hotel manager to a review of their
+// hotel.
+func (s *Server) replyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	reviewId, text := r.PostFormValue("reviewId"), r.PostFormValue("text")
+	if reviewId == "" || text == "" {
+		http.Error(w, "Please specify reviewId and text", http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.reviewClient.ReplyToReview(ctx, &review.ReplyRequest{ReviewId: reviewId, Text: text})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Reply posted.",
+		"review":  res,
+	})
+}
+
+// addRatings adds the average rating and the number of reviews to the
+// properties of the hotels of a GeoJSON response. Hotels are still shown
+// without them when the review service fails.
+func (s *Server) addRatings(ctx context.Context, res map[string]interface{}) {
+	features := res["features"].([]interface{})
+	if len(features) == 0 {
+		return
+	}
+	hotelIds := make([]string, 0, len(features))
+	for _, f := range features {
+		hotelIds = append(hotelIds, f.(map[string]interface{})["id"].(string))
+	}
+
+	summaryResp, err := s.reviewClient.GetRatingSummary(ctx, &review.SummaryRequest{HotelIds: hotelIds})
+	if err != nil {
+		log.Error().Msgf("Failed to get rating summaries: %v", err)
+		return
+	}
+
+	for i, f := range features {
+		if i >= len(summaryResp.Summaries) {
+			break
+		}
+		sum := summaryResp.Summaries[i]
+		properties := f.(map[string]interface{})["properties"].(map[string]interface{})
+		properties["review_count"] = sum.Count
+		if sum.Count > 0 {
+			properties["average_rating"] = sum.Average
+		}
+	}
+}
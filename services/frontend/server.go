@@ -8,15 +8,23 @@ import (
 	"io/fs"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"hotelReservation/auth"
+	"hotelReservation/auth/oidc"
+	"hotelReservation/blobstore"
 	"hotelReservation/dialer"
 	// oteltracing "hotelReservation/oteltracing"
 	_ "github.com/mbobakov/grpc-consul-resolver"
 	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"hotelReservation/registry"
 	attractions "hotelReservation/services/attractions/proto"
+	geo "hotelReservation/services/geo/proto"
 	profile "hotelReservation/services/profile/proto"
 	recommendation "hotelReservation/services/recommendation/proto"
 	reservation "hotelReservation/services/reservation/proto"
@@ -37,12 +45,15 @@ var (
 // Server implements frontend service
 type Server struct {
 	searchClient         search.SearchClient
+	geoClient            geo.GeoClient
 	profileClient        profile.ProfileClient
 	recommendationClient recommendation.RecommendationClient
 	userClient           user.UserClient
 	reviewClient         review.ReviewClient
 	attractionsClient    attractions.AttractionsClient
 	reservationClient    reservation.ReservationClient
+	tokenKeys            tokenKeys
+	oidcProvider         oidc.LazyProvider
 
 	KnativeDns     string
 	IpAddr         string
@@ -51,6 +62,13 @@ type Server struct {
 	Tracer         trace.Tracer
 	Registry       *registry.Client
 	TracerProvider trace.TracerProvider
+	// OIDC is the OpenID Connect client of the /oidc/ login, disabled
+	// without an issuer.
+	OIDC oidc.Config
+	// Images stores the uploaded images of reviews and hotels, of at most
+	// MaxImageBytes each.
+	Images        blobstore.Store
+	MaxImageBytes int64
 }
 
 // Run the server
@@ -72,6 +90,11 @@ func (s *Server) Run() error {
 		return err
 	}
 
+	// if err := s.initGeoClient(ctx, "srv-geo"); err != nil {
+	if err := s.initGeoClient(ctx, "geo-hotel-hotelres:8083"); err != nil {
+		return err
+	}
+
 	// if err := s.initProfileClient(ctx, "srv-profile"); err != nil {
 	if err := s.initProfileClient(ctx, "profile-hotel-hotelres:8081"); err != nil {
 		return err
@@ -86,6 +109,10 @@ func (s *Server) Run() error {
 	if err := s.initUserClient(ctx, "user-hotel-hotelres:8086"); err != nil {
 		return err
 	}
+	if err := s.refreshKeys(ctx); err != nil {
+		log.Error().Msgf("Failed to fetch token keys: %v", err)
+	}
+	go s.refreshKeysLoop(ctx)
 
 	// if err := s.initReservation(ctx, "srv-reservation"); err != nil {
 	if err := s.initReservation(ctx, "reservation-hotel-hotelres:8087"); err != nil {
@@ -93,12 +120,12 @@ func (s *Server) Run() error {
 	}
 
 	// if err := s.initReviewClient(ctx, "srv-review"); err != nil {
-	if err := s.initReviewClient(ctx, "rate-hotel-hotelres:8084"); err != nil {
+	if err := s.initReviewClient(ctx, "review-hotel-hotelres:8088"); err != nil {
 		return err
 	}
 
 	// if err := s.initAttractionsClient(ctx, "srv-attractions"); err != nil {
-	if err := s.initAttractionsClient(ctx, "geo-hotel-hotelres:8083"); err != nil {
+	if err := s.initAttractionsClient(ctx, "attractions-hotel-hotelres:8089"); err != nil {
 		return err
 	}
 
@@ -111,13 +138,29 @@ func (s *Server) Run() error {
 	mux.Handle("/", otelhttp.NewHandler(fileServer, "static-files"))
 	// Wrap each handler with OpenTelemetry
 	mux.Handle("/hotels", otelhttp.NewHandler(http.HandlerFunc(s.searchHandler), "hotels"))
-	mux.Handle("/recommendations", otelhttp.NewHandler(http.HandlerFunc(s.recommendHandler), "recommendations"))
-	mux.Handle("/user", otelhttp.NewHandler(http.HandlerFunc(s.userHandler), "user"))
-	mux.Handle("/review", otelhttp.NewHandler(http.HandlerFunc(s.reviewHandler), "review"))
-	mux.Handle("/restaurants", otelhttp.NewHandler(http.HandlerFunc(s.restaurantHandler), "restaurants"))
-	mux.Handle("/museums", otelhttp.NewHandler(http.HandlerFunc(s.museumHandler), "museums"))
-	mux.Handle("/cinema", otelhttp.NewHandler(http.HandlerFunc(s.cinemaHandler), "cinema"))
-	mux.Handle("/reservation", otelhttp.NewHandler(http.HandlerFunc(s.reservationHandler), "reservation"))
+	mux.Handle("/clusters", otelhttp.NewHandler(http.HandlerFunc(s.clusterHandler), "clusters"))
+	mux.Handle("/recommendations", otelhttp.NewHandler(s.optionallyAuthenticated(s.recommendHandler), "recommendations"))
+	mux.Handle("/user", otelhttp.NewHandler(s.authenticated(s.userHandler), "user"))
+	mux.Handle("/login", otelhttp.NewHandler(http.HandlerFunc(s.loginHandler), "login"))
+	mux.Handle("/oidc/login", otelhttp.NewHandler(http.HandlerFunc(s.oidcLoginHandler), "oidc/login"))
+	mux.Handle("/oidc/callback", otelhttp.NewHandler(http.HandlerFunc(s.oidcCallbackHandler), "oidc/callback"))
+	mux.Handle("/user/signup", otelhttp.NewHandler(http.HandlerFunc(s.signupHandler), "user/signup"))
+	mux.Handle("/user/password", otelhttp.NewHandler(http.HandlerFunc(s.passwordHandler), "user/password"))
+	mux.Handle("/user/role", otelhttp.NewHandler(s.authenticated(s.roleHandler), "user/role"))
+	mux.Handle("/review", otelhttp.NewHandler(s.authenticated(s.reviewHandler), "review"))
+	mux.Handle("/review/submit", otelhttp.NewHandler(s.authenticated(s.submitReviewHandler), "review/submit"))
+	mux.Handle("/review/moderation", otelhttp.NewHandler(s.authenticated(s.moderationQueueHandler), "review/moderation"))
+	mux.Handle("/review/moderate", otelhttp.NewHandler(s.authenticated(s.moderateReviewHandler), "review/moderate"))
+	mux.Handle("/review/images", otelhttp.NewHandler(s.authenticated(s.reviewImagesHandler), "review/images"))
+	mux.Handle("/review/helpful", otelhttp.NewHandler(s.authenticated(s.helpfulHandler), "review/helpful"))
+	mux.Handle("/review/reply", otelhttp.NewHandler(s.authenticated(s.replyHandler), "review/reply"))
+	mux.Handle("/hotels/images", otelhttp.NewHandler(s.authenticated(s.hotelImagesHandler), "hotels/images"))
+	mux.Handle("/images/", otelhttp.NewHandler(http.HandlerFunc(s.imageHandler), "images"))
+	mux.Handle("/attractions", otelhttp.NewHandler(s.authenticated(s.attractionsHandler), "attractions"))
+	mux.Handle("/restaurants", otelhttp.NewHandler(s.authenticated(s.restaurantHandler), "restaurants"))
+	mux.Handle("/museums", otelhttp.NewHandler(s.authenticated(s.museumHandler), "museums"))
+	mux.Handle("/cinema", otelhttp.NewHandler(s.authenticated(s.cinemaHandler), "cinema"))
+	mux.Handle("/reservation", otelhttp.NewHandler(s.authenticated(s.reservationHandler), "reservation"))
 	log.Trace().Msg("frontend starts serving")
 	tlsconfig := tls.GetHttpsOpt()
 	srv := &http.Server{
@@ -143,6 +186,15 @@ func (s *Server) initSearchClient(ctx context.Context, name string) error {
 	return nil
 }
 
+func (s *Server) initGeoClient(ctx context.Context, name string) error {
+	conn, err := s.getGprcConn(ctx, name)
+	if err != nil {
+		return fmt.Errorf("dialer error: %v", err)
+	}
+	s.geoClient = geo.NewGeoClient(conn)
+	return nil
+}
+
 func (s *Server) initReviewClient(ctx context.Context, name string) error {
 	conn, err := s.getGprcConn(ctx, name)
 	if err != nil {
@@ -232,49 +284,78 @@ func (s *Server) searchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// lan/lon from query params
-	sLat, sLon := r.URL.Query().Get("lat"), r.URL.Query().Get("lon")
-	if sLat == "" || sLon == "" {
-		http.Error(w, "Please specify location params", http.StatusBadRequest)
-		return
-	}
-
-	Lat, _ := strconv.ParseFloat(sLat, 32)
-	lat := float32(Lat)
-	Lon, _ := strconv.ParseFloat(sLon, 32)
-	lon := float32(Lon)
+	var hotelIds []string
+	if sBbox, sPolygon := r.URL.Query().Get("bbox"), r.URL.Query().Get("polygon"); sBbox != "" || sPolygon != "" {
+		// viewport mode: hotels inside a bounding box or polygon
+		var (
+			geoResp *geo.Result
+			err     error
+		)
+		if sBbox != "" {
+			boundsReq, perr := parseBbox(sBbox)
+			if perr != nil {
+				http.Error(w, perr.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Trace().Msgf("SEARCH [bbox: %v, inDate: %v, outDate: %v]", sBbox, inDate, outDate)
+			geoResp, err = s.geoClient.WithinBounds(ctx, boundsReq)
+		} else {
+			polygonReq, perr := parsePolygon(sPolygon)
+			if perr != nil {
+				http.Error(w, perr.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Trace().Msgf("SEARCH [polygon: %v, inDate: %v, outDate: %v]", sPolygon, inDate, outDate)
+			geoResp, err = s.geoClient.WithinPolygon(ctx, polygonReq)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), grpcHTTPStatus(err))
+			return
+		}
+		hotelIds = geoResp.HotelIds
+	} else {
+		// lan/lon from query params
+		sLat, sLon := r.URL.Query().Get("lat"), r.URL.Query().Get("lon")
+		if sLat == "" || sLon == "" {
+			http.Error(w, "Please specify location params", http.StatusBadRequest)
+			return
+		}
 
-	log.Trace().Msg("starts searchHandler querying downstream")
+		Lat, _ := strconv.ParseFloat(sLat, 32)
+		lat := float32(Lat)
+		Lon, _ := strconv.ParseFloat(sLon, 32)
+		lon := float32(Lon)
+
+		log.Trace().Msg("starts searchHandler querying downstream")
+
+		log.Trace().Msgf("SEARCH [lat: %v, lon: %v, inDate: %v, outDate: %v", lat, lon, inDate, outDate)
+		// search for best hotels
+		sc := trace.SpanContextFromContext(ctx)
+		fmt.Printf("[CLIENT] about to call Search, trace=%s span=%s\n",sc.TraceID(), sc.SpanID())
+		searchResp, err := s.searchClient.Nearby(ctx, &search.NearbyRequest{
+			Lat:     lat,
+			Lon:     lon,
+			InDate:  inDate,
+			OutDate: outDate,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-	log.Trace().Msgf("SEARCH [lat: %v, lon: %v, inDate: %v, outDate: %v", lat, lon, inDate, outDate)
-	// search for best hotels
-	sc := trace.SpanContextFromContext(ctx)
-	fmt.Printf("[CLIENT] about to call Search, trace=%s span=%s\n",sc.TraceID(), sc.SpanID())
-	searchResp, err := s.searchClient.Nearby(ctx, &search.NearbyRequest{
-		Lat:     lat,
-		Lon:     lon,
-		InDate:  inDate,
-		OutDate: outDate,
-	})
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		log.Trace().Msg("SearchHandler gets searchResp")
+		//for _, hid := range searchResp.HotelIds {
+		//	log.Trace().Msgf("Search Handler hotelId = %s", hid)
+		//}
+		hotelIds = searchResp.HotelIds
 	}
 
-	log.Trace().Msg("SearchHandler gets searchResp")
-	//for _, hid := range searchResp.HotelIds {
-	//	log.Trace().Msgf("Search Handler hotelId = %s", hid)
-	//}
-
-	// grab locale from query params or default to en
-	locale := r.URL.Query().Get("locale")
-	if locale == "" {
-		locale = "en"
-	}
+	// grab locale from query params, then Accept-Language, or default to en
+	locale := localeFromRequest(r)
 
 	reservationResp, err := s.reservationClient.CheckAvailability(ctx, &reservation.Request{
 		CustomerName: "",
-		HotelId:      searchResp.HotelIds,
+		HotelId:      hotelIds,
 		InDate:       inDate,
 		OutDate:      outDate,
 		RoomNumber:   1,
@@ -292,16 +373,56 @@ func (s *Server) searchHandler(w http.ResponseWriter, r *http.Request) {
 	profileResp, err := s.profileClient.GetProfiles(ctx, &profile.Request{
 		HotelIds: reservationResp.HotelId,
 		Locale:   locale,
+		Fields:   searchFields(r.URL.Query().Get("fields")),
 	})
 	if err != nil {
 		log.Error().Msg("SearchHandler GetProfiles failed")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), grpcHTTPStatus(err))
 		return
 	}
 
 	log.Trace().Msg("searchHandler gets profileResp")
 
-	json.NewEncoder(w).Encode(geoJSONResponse(profileResp.Hotels))
+	res := geoJSONResponse(profileResp.Hotels)
+	s.addRatings(ctx, res)
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func (s *Server) clusterHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	ctx := r.Context()
+
+	sBbox, sZoom := r.URL.Query().Get("bbox"), r.URL.Query().Get("zoom")
+	if sBbox == "" || sZoom == "" {
+		http.Error(w, "Please specify bbox and zoom params", http.StatusBadRequest)
+		return
+	}
+
+	boundsReq, err := parseBbox(sBbox)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	zoom, err := strconv.Atoi(sZoom)
+	if err != nil {
+		http.Error(w, "Please check zoom param", http.StatusBadRequest)
+		return
+	}
+
+	clusterResp, err := s.geoClient.Clusters(ctx, &geo.ClusterRequest{
+		MinLat: boundsReq.MinLat,
+		MinLon: boundsReq.MinLon,
+		MaxLat: boundsReq.MaxLat,
+		MaxLon: boundsReq.MaxLon,
+		Zoom:   int32(zoom),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), grpcHTTPStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(clusterGeoJSONResponse(clusterResp.Clusters))
 }
 
 func (s *Server) recommendHandler(w http.ResponseWriter, r *http.Request) {
@@ -318,27 +439,35 @@ func (s *Server) recommendHandler(w http.ResponseWriter, r *http.Request) {
 	lon := float64(Lon)
 
 	require := r.URL.Query().Get("require")
-	if require != "dis" && require != "rate" && require != "price" {
-		http.Error(w, "Please specify require params", http.StatusBadRequest)
+	weights, err := parseWeights(r.URL.Query().Get("weights"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	k := 0
+	if sK := r.URL.Query().Get("k"); sK != "" {
+		if k, err = strconv.Atoi(sK); err != nil {
+			http.Error(w, "Please check k param", http.StatusBadRequest)
+			return
+		}
+	}
 
-	// recommend hotels
+	// recommend hotels, personalized for logged in users
 	recResp, err := s.recommendationClient.GetRecommendations(ctx, &recommendation.Request{
 		Require: require,
 		Lat:     float64(lat),
 		Lon:     float64(lon),
+		Weights:      weights,
+		K:            int32(k),
+		CustomerName: auth.FromContext(ctx).Username,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), grpcHTTPStatus(err))
 		return
 	}
 
-	// grab locale from query params or default to en
-	locale := r.URL.Query().Get("locale")
-	if locale == "" {
-		locale = "en"
-	}
+	// grab locale from query params, then Accept-Language, or default to en
+	locale := localeFromRequest(r)
 
 	// hotel profiles
 	profileResp, err := s.profileClient.GetProfiles(ctx, &profile.Request{
@@ -350,32 +479,23 @@ func (s *Server) recommendHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	json.NewEncoder(w).Encode(geoJSONResponse(profileResp.Hotels))
+	res := geoJSONResponse(profileResp.Hotels)
+	scores := make(map[string]float64, len(recResp.Hotels))
+	for _, h := range recResp.Hotels {
+		scores[h.HotelId] = h.Score
+	}
+	for _, f := range res["features"].([]interface{}) {
+		f := f.(map[string]interface{})
+		f["properties"].(map[string]interface{})["score"] = scores[f["id"].(string)]
+	}
+	s.addRatings(ctx, res)
+
+	json.NewEncoder(w).Encode(res)
 }
 
 func (s *Server) reviewHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	ctx := r.Context()
-	username, password := r.URL.Query().Get("username"), r.URL.Query().Get("password")
-	if username == "" || password == "" {
-		http.Error(w, "Please specify username and password", http.StatusBadRequest)
-		return
-	}
-
-	// Check username and password
-	recResp, err := s.userClient.CheckUser(ctx, &user.Request{
-		Username: username,
-		Password: password,
-	})
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	str := "Logged-in successfully!"
-	if recResp.Correct == false {
-		str = "Failed. Please check your username and password. "
-	}
 
 	hotelId := r.URL.Query().Get("hotelId")
 	if hotelId == "" {
@@ -383,50 +503,99 @@ func (s *Server) reviewHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	revInput := review.Request{HotelId: hotelId}
-
-	revResp, err := s.reviewClient.GetReviews(ctx, &revInput)
+	pageSize := 0
+	if sPageSize := r.URL.Query().Get("pageSize"); sPageSize != "" {
+		var err error
+		if pageSize, err = strconv.Atoi(sPageSize); err != nil {
+			http.Error(w, "Please check pageSize param", http.StatusBadRequest)
+			return
+		}
+	}
+	minRating := 0.0
+	if sMinRating := r.URL.Query().Get("minRating"); sMinRating != "" {
+		var err error
+		if minRating, err = strconv.ParseFloat(sMinRating, 32); err != nil {
+			http.Error(w, "Please check minRating param", http.StatusBadRequest)
+			return
+		}
+	}
 
-	str = "Have reviews = " + strconv.Itoa(len(revResp.Reviews))
-	if len(revResp.Reviews) == 0 {
-		str = "Failed. No Reviews. "
+	revInput := review.Request{
+		HotelId:   hotelId,
+		PageSize:  int32(pageSize),
+		Cursor:    r.URL.Query().Get("cursor"),
+		Sort:      r.URL.Query().Get("sort"),
+		MinRating: float32(minRating),
 	}
 
+	revResp, err := s.reviewClient.GetReviews(ctx, &revInput)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
 		return
 	}
 
+	str := "Have reviews = " + strconv.Itoa(len(revResp.Reviews))
+	if len(revResp.Reviews) == 0 {
+		str = "Failed. No Reviews. "
+	}
+
 	res := map[string]interface{}{
-		"message": str,
+		"message":     str,
+		"reviews":     revResp.Reviews,
+		"next_cursor": revResp.NextCursor,
 	}
 
 	json.NewEncoder(w).Encode(res)
 }
 
-func (s *Server) restaurantHandler(w http.ResponseWriter, r *http.Request) {
+// attractionsHandler returns the attractions close to a hotel, closest
+// first. categories is a comma separated list, all categories when missing.
+func (s *Server) attractionsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	ctx := r.Context()
-	username, password := r.URL.Query().Get("username"), r.URL.Query().Get("password")
-	if username == "" || password == "" {
-		http.Error(w, "Please specify username and password", http.StatusBadRequest)
+
+	q := r.URL.Query()
+	hotelId := q.Get("hotelId")
+	if hotelId == "" {
+		http.Error(w, "Please specify hotelId params", http.StatusBadRequest)
 		return
 	}
+	req := attractions.NearbyRequest{HotelId: hotelId}
+	if v := q.Get("categories"); v != "" {
+		req.Categories = strings.Split(v, ",")
+	}
+	if v := q.Get("radius"); v != "" {
+		radius, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "radius must be a number of kilometers", http.StatusBadRequest)
+			return
+		}
+		req.Radius = radius
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			http.Error(w, "limit must be a number", http.StatusBadRequest)
+			return
+		}
+		req.Limit = int32(limit)
+	}
 
-	// Check username and password
-	recResp, err := s.userClient.CheckUser(ctx, &user.Request{
-		Username: username,
-		Password: password,
-	})
+	res, err := s.attractionsClient.NearbyAttractions(ctx, &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
 		return
 	}
 
-	str := "Logged-in successfully!"
-	if recResp.Correct == false {
-		str = "Failed. Please check your username and password. "
-	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "Have attractions = " + strconv.Itoa(len(res.Attractions)),
+		"attractions": res.Attractions,
+	})
+}
+
+func (s *Server) restaurantHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	ctx := r.Context()
 
 	hotelId := r.URL.Query().Get("hotelId")
 	if hotelId == "" {
@@ -437,17 +606,16 @@ func (s *Server) restaurantHandler(w http.ResponseWriter, r *http.Request) {
 	revInput := attractions.Request{HotelId: hotelId}
 
 	revResp, err := s.attractionsClient.NearbyRest(ctx, &revInput)
-
-	str = "Have restaurants = " + strconv.Itoa(len(revResp.AttractionIds))
-	if len(revResp.AttractionIds) == 0 {
-		str = "Failed. No Restaurants. "
-	}
-
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	str := "Have restaurants = " + strconv.Itoa(len(revResp.AttractionIds))
+	if len(revResp.AttractionIds) == 0 {
+		str = "Failed. No Restaurants. "
+	}
+
 	res := map[string]interface{}{
 		"message": str,
 	}
@@ -459,27 +627,6 @@ func (s *Server) museumHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	ctx := r.Context()
 
-	username, password := r.URL.Query().Get("username"), r.URL.Query().Get("password")
-	if username == "" || password == "" {
-		http.Error(w, "Please specify username and password", http.StatusBadRequest)
-		return
-	}
-
-	// Check username and password
-	recResp, err := s.userClient.CheckUser(ctx, &user.Request{
-		Username: username,
-		Password: password,
-	})
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	str := "Logged-in successfully!"
-	if recResp.Correct == false {
-		str = "Failed. Please check your username and password. "
-	}
-
 	hotelId := r.URL.Query().Get("hotelId")
 	if hotelId == "" {
 		http.Error(w, "Please specify hotelId params", http.StatusBadRequest)
@@ -489,17 +636,16 @@ func (s *Server) museumHandler(w http.ResponseWriter, r *http.Request) {
 	revInput := attractions.Request{HotelId: hotelId}
 
 	revResp, err := s.attractionsClient.NearbyMus(ctx, &revInput)
-
-	str = "Have museums = " + strconv.Itoa(len(revResp.AttractionIds))
-	if len(revResp.AttractionIds) == 0 {
-		str = "Failed. No Museums. "
-	}
-
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	str := "Have museums = " + strconv.Itoa(len(revResp.AttractionIds))
+	if len(revResp.AttractionIds) == 0 {
+		str = "Failed. No Museums. "
+	}
+
 	res := map[string]interface{}{
 		"message": str,
 	}
@@ -511,27 +657,6 @@ func (s *Server) cinemaHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	ctx := r.Context()
 
-	username, password := r.URL.Query().Get("username"), r.URL.Query().Get("password")
-	if username == "" || password == "" {
-		http.Error(w, "Please specify username and password", http.StatusBadRequest)
-		return
-	}
-
-	// Check username and password
-	recResp, err := s.userClient.CheckUser(ctx, &user.Request{
-		Username: username,
-		Password: password,
-	})
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	str := "Logged-in successfully!"
-	if recResp.Correct == false {
-		str = "Failed. Please check your username and password. "
-	}
-
 	hotelId := r.URL.Query().Get("hotelId")
 	if hotelId == "" {
 		http.Error(w, "Please specify hotelId params", http.StatusBadRequest)
@@ -541,17 +666,16 @@ func (s *Server) cinemaHandler(w http.ResponseWriter, r *http.Request) {
 	revInput := attractions.Request{HotelId: hotelId}
 
 	revResp, err := s.attractionsClient.NearbyCinema(ctx, &revInput)
-
-	str = "Have cinemas = " + strconv.Itoa(len(revResp.AttractionIds))
-	if len(revResp.AttractionIds) == 0 {
-		str = "Failed. No Cinemas. "
-	}
-
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	str := "Have cinemas = " + strconv.Itoa(len(revResp.AttractionIds))
+	if len(revResp.AttractionIds) == 0 {
+		str = "Failed. No Cinemas. "
+	}
+
 	res := map[string]interface{}{
 		"message": str,
 	}
@@ -561,33 +685,73 @@ func (s *Server) cinemaHandler(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) userHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
+	id := auth.FromContext(r.Context())
+
+	res := map[string]interface{}{
+		"message":  "Logged in as " + id.Username,
+		"username": id.Username,
+		"role":     id.Role,
+		"hotels":   id.Hotels,
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func (s *Server) signupHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
 	ctx := r.Context()
-	username, password := r.URL.Query().Get("username"), r.URL.Query().Get("password")
+	username, password := r.PostFormValue("username"), r.PostFormValue("password")
 	if username == "" || password == "" {
 		http.Error(w, "Please specify username and password", http.StatusBadRequest)
 		return
 	}
 
-	// Check username and password
-	recResp, err := s.userClient.CheckUser(ctx, &user.Request{
+	_, err := s.userClient.Register(ctx, &user.Request{
 		Username: username,
 		Password: password,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
 		return
 	}
 
-	str := "Login successfully!"
-	if recResp.Correct == false {
-		str = "Failed. Please check your username and password. "
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Sign up successfully!",
+	})
+}
+
+func (s *Server) passwordHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method != http.MethodPost {
+		http.Error(w, "Please use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := auth.WithClientIP(r.Context(), clientIP(r))
+	username, password := r.PostFormValue("username"), r.PostFormValue("password")
+	newPassword := r.PostFormValue("newPassword")
+	if username == "" || password == "" || newPassword == "" {
+		http.Error(w, "Please specify username, password and newPassword", http.StatusBadRequest)
+		return
 	}
 
-	res := map[string]interface{}{
-		"message": str,
+	_, err := s.userClient.ChangePassword(ctx, &user.ChangePasswordRequest{
+		Username:    username,
+		Password:    password,
+		NewPassword: newPassword,
+	})
+	if err != nil {
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
+		return
 	}
 
-	json.NewEncoder(w).Encode(res)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Password changed successfully!",
+	})
 }
 
 func (s *Server) reservationHandler(w http.ResponseWriter, r *http.Request) {
@@ -610,17 +774,10 @@ func (s *Server) reservationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	customerName := r.URL.Query().Get("customerName")
-	if customerName == "" {
-		http.Error(w, "Please specify customerName params", http.StatusBadRequest)
-		return
-	}
-
-	username, password := r.URL.Query().Get("username"), r.URL.Query().Get("password")
-	if username == "" || password == "" {
-		http.Error(w, "Please specify username and password", http.StatusBadRequest)
-		return
-	}
+	// reservations are always made by the logged in user, optionally for
+	// another guest
+	customerName := auth.FromContext(ctx).Username
+	guestName := r.URL.Query().Get("guestName")
 
 	numberOfRoom := 0
 	num := r.URL.Query().Get("number")
@@ -628,31 +785,19 @@ func (s *Server) reservationHandler(w http.ResponseWriter, r *http.Request) {
 		numberOfRoom, _ = strconv.Atoi(num)
 	}
 
-	// Check username and password
-	recResp, err := s.userClient.CheckUser(ctx, &user.Request{
-		Username: username,
-		Password: password,
-	})
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
 	str := "Reserve successfully!"
-	if recResp.Correct == false {
-		str = "Failed. Please check your username and password. "
-	}
 
 	// Make reservation
 	resResp, err := s.reservationClient.MakeReservation(ctx, &reservation.Request{
 		CustomerName: customerName,
+		GuestName:    guestName,
 		HotelId:      []string{hotelId},
 		InDate:       inDate,
 		OutDate:      outDate,
 		RoomNumber:   int32(numberOfRoom),
 	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, status.Convert(err).Message(), grpcHTTPStatus(err))
 		return
 	}
 	if len(resResp.HotelId) == 0 {
@@ -675,9 +820,14 @@ func geoJSONResponse(hs []*profile.Hotel) map[string]interface{} {
 		fs = append(fs, map[string]interface{}{
 			"type": "Feature",
 			"id":   h.Id,
-			"properties": map[string]string{
-				"name":         h.Name,
-				"phone_number": h.PhoneNumber,
+			"properties": map[string]interface{}{
+				"name":           h.Name,
+				"phone_number":   h.PhoneNumber,
+				"amenities":      h.Amenities,
+				"star_rating":    h.StarRating,
+				"check_in_time":  h.CheckInTime,
+				"check_out_time": h.CheckOutTime,
+				"room_types":     roomTypes(h.RoomTypes),
 			},
 			"geometry": map[string]interface{}{
 				"type": "Point",
@@ -695,6 +845,189 @@ func geoJSONResponse(hs []*profile.Hotel) map[string]interface{} {
 	}
 }
 
+// searchResultFields are the profile fields shown in search results.
+var searchResultFields = []string{
+	"name", "phoneNumber", "amenities", "starRating", "checkInTime", "checkOutTime", "roomTypes",
+}
+
+// searchFields returns the profile field mask of a search, from a comma
+// separated fields parameter or searchResultFields. The coordinates are
+// always requested for the GeoJSON geometry.
+func searchFields(fields string) *fieldmaskpb.FieldMask {
+	paths := searchResultFields
+	if fields != "" {
+		paths = strings.Split(fields, ",")
+	}
+	return &fieldmaskpb.FieldMask{Paths: append([]string{"address.lat", "address.lon"}, paths...)}
+}
+
+func roomTypes(rs []*profile.RoomType) []interface{} {
+	rts := []interface{}{}
+	for _, r := range rs {
+		rts = append(rts, map[string]interface{}{
+			"code":          r.Code,
+			"name":          r.Name,
+			"description":   r.Description,
+			"bed_type":      r.BedType,
+			"max_occupancy": r.MaxOccupancy,
+		})
+	}
+	return rts
+}
+
+// return a geoJSON response with one point feature per cluster, carrying
+// the cluster size and the bounding box of its hotels
+func clusterGeoJSONResponse(cs []*geo.Cluster) map[string]interface{} {
+	fs := []interface{}{}
+
+	for _, c := range cs {
+		properties := map[string]interface{}{
+			"cluster":     c.Count > 1,
+			"point_count": c.Count,
+		}
+		if c.HotelId != "" {
+			properties["hotel_id"] = c.HotelId
+		}
+		fs = append(fs, map[string]interface{}{
+			"type":       "Feature",
+			"properties": properties,
+			"bbox": []float32{
+				c.SouthWest.Lon,
+				c.SouthWest.Lat,
+				c.NorthEast.Lon,
+				c.NorthEast.Lat,
+			},
+			"geometry": map[string]interface{}{
+				"type": "Point",
+				"coordinates": []float32{
+					c.Centroid.Lon,
+					c.Centroid.Lat,
+				},
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"type":     "FeatureCollection",
+		"features": fs,
+	}
+}
+
+// parseBbox parses a viewport given as "minLat,minLon,maxLat,maxLon",
+// the order produced by google.maps.LatLngBounds.toUrlValue.
+func parseBbox(bbox string) (*geo.BoundsRequest, error) {
+	parts := strings.Split(bbox, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("Please specify bbox as minLat,minLon,maxLat,maxLon")
+	}
+	vals := make([]float32, 4)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return nil, fmt.Errorf("Please check bbox value %q: %v", part, err)
+		}
+		vals[i] = float32(v)
+	}
+	return &geo.BoundsRequest{
+		MinLat: vals[0],
+		MinLon: vals[1],
+		MaxLat: vals[2],
+		MaxLon: vals[3],
+	}, nil
+}
+
+// parsePolygon parses polygon vertices given as "lat,lon;lat,lon;...".
+func parsePolygon(polygon string) (*geo.PolygonRequest, error) {
+	req := &geo.PolygonRequest{}
+	for _, vertex := range strings.Split(polygon, ";") {
+		parts := strings.Split(vertex, ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Please specify polygon as lat,lon;lat,lon;...")
+		}
+		lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 32)
+		if err != nil {
+			return nil, fmt.Errorf("Please check polygon latitude %q: %v", parts[0], err)
+		}
+		lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 32)
+		if err != nil {
+			return nil, fmt.Errorf("Please check polygon longitude %q: %v", parts[1], err)
+		}
+		req.Vertices = append(req.Vertices, &geo.Point{Lat: float32(lat), Lon: float32(lon)})
+	}
+	return req, nil
+}
+
+// parseWeights parses recommendation weights given as
+// "criterion:weight,criterion:weight", e.g. "dis:2,price:1".
+func parseWeights(weights string) (map[string]float64, error) {
+	if weights == "" {
+		return nil, nil
+	}
+	res := make(map[string]float64)
+	for _, pair := range strings.Split(weights, ",") {
+		parts := strings.Split(pair, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Please check weights format %q, want criterion:weight", pair)
+		}
+		weight, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Please check weight %q: %v", parts[1], err)
+		}
+		res[parts[0]] = weight
+	}
+	return res, nil
+}
+
+// grpcHTTPStatus maps the status code of a backend error to an HTTP status.
+func grpcHTTPStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// localeFromRequest returns the locale query parameter if set, otherwise the
+// preferred language of the Accept-Language header, otherwise "en".
+func localeFromRequest(r *http.Request) string {
+	if locale := r.URL.Query().Get("locale"); locale != "" {
+		return locale
+	}
+
+	locale, best := "en", 0.0
+	for _, lang := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		params := strings.Split(lang, ";")
+		tag := strings.TrimSpace(params[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > best {
+			locale, best = tag, q
+		}
+	}
+	return locale
+}
+
 func checkDataFormat(date string) bool {
 	if len(date) != 10 {
 		return false
@@ -0,0 +1,105 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hailocab/go-geoindex"
+)
+
+const (
+	// maxBoundsSpan is the largest latitude or longitude span, in degrees,
+	// accepted for a bounding box or polygon query.
+	maxBoundsSpan = 2.0
+	// boundsTileSpan keeps every tile queried against the index below the
+	// street level threshold of the clustering index, so that Range returns
+	// hotel points instead of aggregated counts.
+	boundsTileSpan = 0.25
+)
+
+// bounds is a lat/lon bounding box that does not cross the antimeridian.
+type bounds struct {
+	minLat, minLon, maxLat, maxLon float64
+}
+
+func (b bounds) validate() error {
+	if err := b.validateRange(); err != nil {
+		return err
+	}
+	if !b.searchable() {
+		return fmt.Errorf("bounds [%v, %v, %v, %v] span more than %v degrees", b.minLat, b.minLon, b.maxLat, b.maxLon, maxBoundsSpan)
+	}
+	return nil
+}
+
+// validateRange checks that b is a well-formed box, whatever its size.
+func (b bounds) validateRange() error {
+	if b.minLat < -90 || b.maxLat > 90 || b.minLon < -180 || b.maxLon > 180 {
+		return fmt.Errorf("bounds [%v, %v, %v, %v] out of range", b.minLat, b.minLon, b.maxLat, b.maxLon)
+	}
+	if b.minLat > b.maxLat || b.minLon > b.maxLon {
+		return fmt.Errorf("bounds [%v, %v, %v, %v] min must not exceed max", b.minLat, b.minLon, b.maxLat, b.maxLon)
+	}
+	return nil
+}
+
+// searchable reports whether b is small enough to be searched point by point.
+func (b bounds) searchable() bool {
+	return b.maxLat-b.minLat <= maxBoundsSpan && b.maxLon-b.minLon <= maxBoundsSpan
+}
+
+func (b bounds) contains(lat, lon float64) bool {
+	return lat >= b.minLat && lat <= b.maxLat && lon >= b.minLon && lon <= b.maxLon
+}
+
+// tiles splits the box into sub-boxes of at most boundsTileSpan degrees.
+func (b bounds) tiles() []bounds {
+	var ts []bounds
+	for lat := b.minLat; lat <= b.maxLat; lat += boundsTileSpan {
+		for lon := b.minLon; lon <= b.maxLon; lon += boundsTileSpan {
+			ts = append(ts, bounds{
+				minLat: lat,
+				minLon: lon,
+				maxLat: math.Min(lat+boundsTileSpan, b.maxLat),
+				maxLon: math.Min(lon+boundsTileSpan, b.maxLon),
+			})
+		}
+	}
+	return ts
+}
+
+// polygon is a simple polygon given by its vertices in order.
+type polygon []geoindex.GeoPoint
+
+func (p polygon) validate() error {
+	if len(p) < 3 {
+		return fmt.Errorf("polygon needs at least 3 vertices, got %d", len(p))
+	}
+	return p.bounds().validate()
+}
+
+// bounds returns the bounding box of the polygon.
+func (p polygon) bounds() bounds {
+	b := bounds{math.MaxFloat64, math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64}
+	for _, v := range p {
+		b.minLat = math.Min(b.minLat, v.Plat)
+		b.minLon = math.Min(b.minLon, v.Plon)
+		b.maxLat = math.Max(b.maxLat, v.Plat)
+		b.maxLon = math.Max(b.maxLon, v.Plon)
+	}
+	return b
+}
+
+// contains reports whether the point lies inside the polygon, using the
+// even-odd ray casting rule on the lat/lon plane.
+func (p polygon) contains(lat, lon float64) bool {
+	in := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		vi, vj := p[i], p[j]
+		if (vi.Plat > lat) != (vj.Plat > lat) &&
+			lon < (vj.Plon-vi.Plon)*(lat-vi.Plat)/(vj.Plat-vi.Plat)+vi.Plon {
+			in = !in
+		}
+	}
+	return in
+}
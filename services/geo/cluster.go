@@ -0,0 +1,164 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hailocab/go-geoindex"
+	pb "hotelReservation/services/geo/proto"
+)
+
+const (
+	maxZoom = 22
+	// clusterPixels is the width of a cluster cell on screen, for 256 pixel
+	// web map tiles.
+	clusterPixels = 64
+
+	// The clustering index answers ranges with a diagonal of at least
+	// indexStreetLevel with counts per indexCityCell cell, and ranges with a
+	// diagonal of at least indexCityLevel with counts per indexWorldCell
+	// cell. These mirror the levels of geoindex.NewClusteringIndex.
+	indexStreetLevel = 45000.0
+	indexCityLevel   = 1000000.0
+	indexCityCell    = 10000.0
+	indexWorldCell   = 500000.0
+	// The cell lengths of a degree used by the geoindex package.
+	indexLatDegree = 111000.0
+	indexLonDegree = 85000.0
+)
+
+// cluster accumulates the points of one cluster cell.
+type cluster struct {
+	latSum, lonSum float64
+	count          int
+	area           bounds
+	hotelId        string
+}
+
+func (c *cluster) add(lat, lon float64, count int, area bounds) {
+	if c.count == 0 {
+		c.area = area
+	} else {
+		c.area.minLat = math.Min(c.area.minLat, area.minLat)
+		c.area.minLon = math.Min(c.area.minLon, area.minLon)
+		c.area.maxLat = math.Max(c.area.maxLat, area.maxLat)
+		c.area.maxLon = math.Max(c.area.maxLon, area.maxLon)
+	}
+	c.latSum += lat * float64(count)
+	c.lonSum += lon * float64(count)
+	c.count += count
+}
+
+func (c *cluster) toProto() *pb.Cluster {
+	return &pb.Cluster{
+		Count: int32(c.count),
+		Centroid: &pb.Point{
+			Lat: float32(c.latSum / float64(c.count)),
+			Lon: float32(c.lonSum / float64(c.count)),
+		},
+		SouthWest: &pb.Point{Lat: float32(c.area.minLat), Lon: float32(c.area.minLon)},
+		NorthEast: &pb.Point{Lat: float32(c.area.maxLat), Lon: float32(c.area.maxLon)},
+		HotelId:   c.hotelId,
+	}
+}
+
+// clusterCellSpan returns the size in degrees of a cluster cell at zoom.
+func clusterCellSpan(zoom int32) float64 {
+	return 360.0 / math.Exp2(float64(zoom)) * clusterPixels / 256.0
+}
+
+func validateZoom(zoom int32) error {
+	if zoom < 0 || zoom > maxZoom {
+		return fmt.Errorf("zoom %d out of range [0, %d]", zoom, maxZoom)
+	}
+	return nil
+}
+
+// indexCell returns the area of the count index cell holding a count point
+// returned for a range query with the given diagonal.
+func indexCell(p geoindex.Point, diagonal float64) bounds {
+	resolution := indexCityCell
+	if diagonal >= indexCityLevel {
+		resolution = indexWorldCell
+	}
+	latSpan := resolution / indexLatDegree
+	lonSpan := resolution / indexLonDegree
+	x := math.Floor((p.Lat() + 90) / latSpan)
+	y := math.Floor((p.Lon() + 180) / lonSpan)
+	return bounds{
+		minLat: x*latSpan - 90,
+		minLon: y*lonSpan - 180,
+		maxLat: (x+1)*latSpan - 90,
+		maxLon: (y+1)*lonSpan - 180,
+	}
+}
+
+// getClusters groups the hotels in b into cells of a grid sized for zoom.
+// Small viewports are clustered from individual hotel points. Larger ones
+// start from the counts that the clustering index keeps per cell.
+func (s *Server) getClusters(b bounds, zoom int32) []*pb.Cluster {
+	span := clusterCellSpan(zoom)
+	cells := make(map[[2]int]*cluster)
+	addTo := func(lat, lon float64) *cluster {
+		key := [2]int{int(math.Floor(lat / span)), int(math.Floor(lon / span))}
+		c, ok := cells[key]
+		if !ok {
+			c = &cluster{}
+			cells[key] = c
+		}
+		return c
+	}
+
+	addHotel := func(p geoindex.Point) {
+		c := addTo(p.Lat(), p.Lon())
+		c.add(p.Lat(), p.Lon(), 1, bounds{p.Lat(), p.Lon(), p.Lat(), p.Lon()})
+		if c.count == 1 {
+			c.hotelId = p.Id()
+		} else {
+			c.hotelId = ""
+		}
+	}
+
+	if b.searchable() {
+		for _, p := range s.getPointsWithin(b) {
+			addHotel(p)
+		}
+	} else {
+		topLeft := &geoindex.GeoPoint{Plat: b.maxLat, Plon: b.minLon}
+		bottomRight := &geoindex.GeoPoint{Plat: b.minLat, Plon: b.maxLon}
+		diagonal := float64(geoindex.Distance(topLeft, bottomRight))
+
+		s.mu.RLock()
+		counts := s.index.Range(topLeft, bottomRight)
+		s.mu.RUnlock()
+
+		for _, p := range counts {
+			cp, ok := p.(*geoindex.CountPoint)
+			if !ok {
+				// boxes with a diagonal under indexStreetLevel, which span
+				// many degrees of longitude only near the poles, are
+				// answered with the hotels themselves
+				if b.contains(p.Lat(), p.Lon()) {
+					addHotel(p)
+				}
+				continue
+			}
+			count, _ := cp.Count.(int)
+			if count <= 0 {
+				continue
+			}
+			addTo(cp.Lat(), cp.Lon()).add(cp.Lat(), cp.Lon(), count, indexCell(cp, diagonal))
+		}
+	}
+
+	res := make([]*pb.Cluster, 0, len(cells))
+	for _, c := range cells {
+		res = append(res, c.toProto())
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Count > res[j].Count
+	})
+
+	return res
+}
@@ -0,0 +1,47 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/hailocab/go-geoindex"
+)
+
+func TestGetClusters(t *testing.T) {
+	s := &Server{index: buildGeoIndex([]*point{
+		{"1", 37.7867, -122.4112},
+		{"2", 37.7854, -122.4005},
+		{"3", 89.91, 1},
+		{"4", 89.92, 5},
+	})}
+
+	// wider than maxBoundsSpan, but small enough for the index to return
+	// hotel points rather than counts
+	pole := bounds{89.9, 0, 89.95, 10}
+	topLeft := &geoindex.GeoPoint{Plat: pole.maxLat, Plon: pole.minLon}
+	bottomRight := &geoindex.GeoPoint{Plat: pole.minLat, Plon: pole.maxLon}
+	if d := float64(geoindex.Distance(topLeft, bottomRight)); pole.searchable() || d >= indexStreetLevel {
+		t.Fatalf("bounds near the pole are searchable or have a diagonal of %v m", d)
+	}
+
+	tests := []struct {
+		name string
+		b    bounds
+		zoom int32
+		want int
+	}{
+		{"searchable", bounds{37.7, -122.5, 37.8, -122.3}, 12, 2},
+		{"counts", bounds{30, -130, 40, -110}, 3, 2},
+		{"near the pole", pole, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			for _, c := range s.getClusters(tt.b, tt.zoom) {
+				count += int(c.Count)
+			}
+			if count != tt.want {
+				t.Errorf("clusters hold %d hotels, want %d", count, tt.want)
+			}
+		})
+	}
+}
@@ -19,7 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Geo_Nearby_FullMethodName = "/geo.Geo/Nearby"
+	Geo_Nearby_FullMethodName              = "/geo.Geo/Nearby"
+	Geo_WithinBounds_FullMethodName        = "/geo.Geo/WithinBounds"
+	Geo_WithinPolygon_FullMethodName       = "/geo.Geo/WithinPolygon"
+	Geo_Clusters_FullMethodName            = "/geo.Geo/Clusters"
+	Geo_UpsertHotelLocation_FullMethodName = "/geo.Geo/UpsertHotelLocation"
+	Geo_RemoveHotel_FullMethodName         = "/geo.Geo/RemoveHotel"
 )
 
 // GeoClient is the client API for Geo service.
@@ -28,6 +33,16 @@ const (
 type GeoClient interface {
 	// Finds the hotels contained nearby the current lat/lon.
 	Nearby(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// Finds the hotels contained in a lat/lon bounding box.
+	WithinBounds(ctx context.Context, in *BoundsRequest, opts ...grpc.CallOption) (*Result, error)
+	// Finds the hotels contained in a polygon.
+	WithinPolygon(ctx context.Context, in *PolygonRequest, opts ...grpc.CallOption) (*Result, error)
+	// Groups the hotels in a map viewport into clusters for a zoom level.
+	Clusters(ctx context.Context, in *ClusterRequest, opts ...grpc.CallOption) (*ClusterResult, error)
+	// Adds a hotel to the index or moves it to a new location.
+	UpsertHotelLocation(ctx context.Context, in *HotelLocation, opts ...grpc.CallOption) (*UpdateResult, error)
+	// Removes a hotel from the index.
+	RemoveHotel(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*UpdateResult, error)
 }
 
 type geoClient struct {
@@ -47,12 +62,67 @@ func (c *geoClient) Nearby(ctx context.Context, in *Request, opts ...grpc.CallOp
 	return out, nil
 }
 
+func (c *geoClient) WithinBounds(ctx context.Context, in *BoundsRequest, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, Geo_WithinBounds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geoClient) WithinPolygon(ctx context.Context, in *PolygonRequest, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, Geo_WithinPolygon_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geoClient) Clusters(ctx context.Context, in *ClusterRequest, opts ...grpc.CallOption) (*ClusterResult, error) {
+	out := new(ClusterResult)
+	err := c.cc.Invoke(ctx, Geo_Clusters_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geoClient) UpsertHotelLocation(ctx context.Context, in *HotelLocation, opts ...grpc.CallOption) (*UpdateResult, error) {
+	out := new(UpdateResult)
+	err := c.cc.Invoke(ctx, Geo_UpsertHotelLocation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geoClient) RemoveHotel(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*UpdateResult, error) {
+	out := new(UpdateResult)
+	err := c.cc.Invoke(ctx, Geo_RemoveHotel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GeoServer is the server API for Geo service.
 // All implementations must embed UnimplementedGeoServer
 // for forward compatibility
 type GeoServer interface {
 	// Finds the hotels contained nearby the current lat/lon.
 	Nearby(context.Context, *Request) (*Result, error)
+	// Finds the hotels contained in a lat/lon bounding box.
+	WithinBounds(context.Context, *BoundsRequest) (*Result, error)
+	// Finds the hotels contained in a polygon.
+	WithinPolygon(context.Context, *PolygonRequest) (*Result, error)
+	// Groups the hotels in a map viewport into clusters for a zoom level.
+	Clusters(context.Context, *ClusterRequest) (*ClusterResult, error)
+	// Adds a hotel to the index or moves it to a new location.
+	UpsertHotelLocation(context.Context, *HotelLocation) (*UpdateResult, error)
+	// Removes a hotel from the index.
+	RemoveHotel(context.Context, *RemoveRequest) (*UpdateResult, error)
 	mustEmbedUnimplementedGeoServer()
 }
 
@@ -63,6 +133,21 @@ type UnimplementedGeoServer struct {
 func (UnimplementedGeoServer) Nearby(context.Context, *Request) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Nearby not implemented")
 }
+func (UnimplementedGeoServer) WithinBounds(context.Context, *BoundsRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WithinBounds not implemented")
+}
+func (UnimplementedGeoServer) WithinPolygon(context.Context, *PolygonRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WithinPolygon not implemented")
+}
+func (UnimplementedGeoServer) Clusters(context.Context, *ClusterRequest) (*ClusterResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Clusters not implemented")
+}
+func (UnimplementedGeoServer) UpsertHotelLocation(context.Context, *HotelLocation) (*UpdateResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertHotelLocation not implemented")
+}
+func (UnimplementedGeoServer) RemoveHotel(context.Context, *RemoveRequest) (*UpdateResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveHotel not implemented")
+}
 func (UnimplementedGeoServer) mustEmbedUnimplementedGeoServer() {}
 
 // UnsafeGeoServer may be embedded to opt out of forward compatibility for this service.
@@ -94,6 +179,96 @@ func _Geo_Nearby_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Geo_WithinBounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BoundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoServer).WithinBounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geo_WithinBounds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoServer).WithinBounds(ctx, req.(*BoundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geo_WithinPolygon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolygonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoServer).WithinPolygon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geo_WithinPolygon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoServer).WithinPolygon(ctx, req.(*PolygonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geo_Clusters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoServer).Clusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geo_Clusters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoServer).Clusters(ctx, req.(*ClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geo_UpsertHotelLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HotelLocation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoServer).UpsertHotelLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geo_UpsertHotelLocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoServer).UpsertHotelLocation(ctx, req.(*HotelLocation))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geo_RemoveHotel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeoServer).RemoveHotel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geo_RemoveHotel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeoServer).RemoveHotel(ctx, req.(*RemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Geo_ServiceDesc is the grpc.ServiceDesc for Geo service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -105,6 +280,26 @@ var Geo_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Nearby",
 			Handler:    _Geo_Nearby_Handler,
 		},
+		{
+			MethodName: "WithinBounds",
+			Handler:    _Geo_WithinBounds_Handler,
+		},
+		{
+			MethodName: "WithinPolygon",
+			Handler:    _Geo_WithinPolygon_Handler,
+		},
+		{
+			MethodName: "Clusters",
+			Handler:    _Geo_Clusters_Handler,
+		},
+		{
+			MethodName: "UpsertHotelLocation",
+			Handler:    _Geo_UpsertHotelLocation_Handler,
+		},
+		{
+			MethodName: "RemoveHotel",
+			Handler:    _Geo_RemoveHotel_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/geo/proto/geo.proto",
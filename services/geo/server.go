@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,13 +13,18 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	otelgrpc "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
 	"hotelReservation/registry"
 	pb "hotelReservation/services/geo/proto"
+	"hotelReservation/snapshot"
 	"hotelReservation/tls"
 )
 
@@ -31,8 +38,15 @@ const (
 type Server struct {
 	pb.UnimplementedGeoServer
 
-	index *geoindex.ClusteringIndex
+	// mu guards index, which is read by queries and replaced or
+	// modified by location updates and the index watcher.
+	mu    sync.RWMutex
+	index *snapshot.Index
 	uuid  string
+	// snapMu serializes snapshot writes, and snapDirty is set when the
+	// index changed since the last one.
+	snapMu    sync.Mutex
+	snapDirty atomic.Bool
 
 	Registry       *registry.Client
 	Tracer         trace.Tracer
@@ -40,6 +54,21 @@ type Server struct {
 	IpAddr         string
 	MongoClient    *mongo.Client
 	TracerProvider trace.TracerProvider
+	// WatchIndex applies changes made to geo-db.geo by other writers to
+	// the in-memory index.
+	WatchIndex bool
+	// PollInterval is used to reload the index when change streams are
+	// not supported by the MongoDB deployment.
+	PollInterval time.Duration
+	// SnapshotPath is where the index is saved and loaded from at startup.
+	// Snapshots are disabled when empty.
+	SnapshotPath string
+	// SnapshotMaxAge is how old a snapshot may be and still be loaded
+	// instead of reading geo-db.geo. Zero means no limit.
+	SnapshotMaxAge time.Duration
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -49,7 +78,15 @@ func (s *Server) Run() error {
 	}
 
 	if s.index == nil {
-		s.index = newGeoIndex(s.MongoClient)
+		s.index = s.loadIndex()
+	}
+
+	if s.WatchIndex {
+		go s.watchIndex(context.Background())
+	}
+
+	if s.SnapshotPath != "" {
+		go s.flushSnapshots(context.Background())
 	}
 
 	s.uuid = uuid.New().String()
@@ -62,6 +99,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			otelgrpc.StreamServerInterceptor(
@@ -111,6 +149,7 @@ func (s *Server) Run() error {
 
 // Shutdown cleans up any processes
 func (s *Server) Shutdown() {
+	s.flushSnapshot()
 	s.Registry.Deregister(s.uuid)
 }
 
@@ -141,6 +180,9 @@ func (s *Server) getNearbyPoints(ctx context.Context, lat, lon float64) []geoind
 		Plon: lon,
 	}
 
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.index.KNearest(
 		center,
 		maxSearchResults,
@@ -150,24 +192,178 @@ func (s *Server) getNearbyPoints(ctx context.Context, lat, lon float64) []geoind
 	)
 }
 
+// WithinBounds returns all hotels inside a lat/lon bounding box.
+func (s *Server) WithinBounds(ctx context.Context, req *pb.BoundsRequest) (*pb.Result, error) {
+	log.Trace().Msgf("In geo WithinBounds")
+
+	b := bounds{
+		minLat: float64(req.MinLat),
+		minLon: float64(req.MinLon),
+		maxLat: float64(req.MaxLat),
+		maxLon: float64(req.MaxLon),
+	}
+	if err := b.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := &pb.Result{}
+	for _, p := range s.getPointsWithin(b) {
+		res.HotelIds = append(res.HotelIds, p.Id())
+	}
+
+	return res, nil
+}
+
+// WithinPolygon returns all hotels inside a polygon.
+func (s *Server) WithinPolygon(ctx context.Context, req *pb.PolygonRequest) (*pb.Result, error) {
+	log.Trace().Msgf("In geo WithinPolygon, vertices = %d", len(req.Vertices))
+
+	poly := make(polygon, 0, len(req.Vertices))
+	for _, v := range req.Vertices {
+		poly = append(poly, geoindex.GeoPoint{Plat: float64(v.Lat), Plon: float64(v.Lon)})
+	}
+	if err := poly.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := &pb.Result{}
+	for _, p := range s.getPointsWithin(poly.bounds()) {
+		if poly.contains(p.Lat(), p.Lon()) {
+			res.HotelIds = append(res.HotelIds, p.Id())
+		}
+	}
+
+	return res, nil
+}
+
+// Clusters returns the hotels inside a map viewport grouped into clusters
+// sized for the zoom level.
+func (s *Server) Clusters(ctx context.Context, req *pb.ClusterRequest) (*pb.ClusterResult, error) {
+	log.Trace().Msgf("In geo Clusters, zoom = %d", req.Zoom)
+
+	b := bounds{
+		minLat: float64(req.MinLat),
+		minLon: float64(req.MinLon),
+		maxLat: float64(req.MaxLat),
+		maxLon: float64(req.MaxLon),
+	}
+	if err := b.validateRange(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateZoom(req.Zoom); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &pb.ClusterResult{Clusters: s.getClusters(b, req.Zoom)}, nil
+}
+
+// getPointsWithin returns the hotel points inside b. The box is queried
+// tile by tile so that the clustering index never answers with counts.
+func (s *Server) getPointsWithin(b bounds) []geoindex.Point {
+	log.Trace().Msgf("In geo getPointsWithin, bounds = %+v", b)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+	points := make([]geoindex.Point, 0)
+	for _, t := range b.tiles() {
+		topLeft := &geoindex.GeoPoint{Plat: t.maxLat, Plon: t.minLon}
+		bottomRight := &geoindex.GeoPoint{Plat: t.minLat, Plon: t.maxLon}
+		for _, p := range s.index.Range(topLeft, bottomRight) {
+			if _, ok := seen[p.Id()]; ok || !b.contains(p.Lat(), p.Lon()) {
+				continue
+			}
+			seen[p.Id()] = struct{}{}
+			points = append(points, p)
+		}
+	}
+
+	return points
+}
+
+// UpsertHotelLocation stores a hotel location and adds or moves the hotel
+// in the index.
+func (s *Server) UpsertHotelLocation(ctx context.Context, req *pb.HotelLocation) (*pb.UpdateResult, error) {
+	log.Trace().Msgf("In geo UpsertHotelLocation, hotelId = %s", req.HotelId)
+
+	if req.HotelId == "" {
+		return nil, status.Error(codes.InvalidArgument, "hotelId must be set")
+	}
+	if req.Lat < -90 || req.Lat > 90 || req.Lon < -180 || req.Lon > 180 {
+		return nil, status.Errorf(codes.InvalidArgument, "location [%v, %v] out of range", req.Lat, req.Lon)
+	}
+
+	p := &point{Pid: req.HotelId, Plat: float64(req.Lat), Plon: float64(req.Lon)}
+	collection := s.MongoClient.Database("geo-db").Collection("geo")
+	updateRes, err := collection.ReplaceOne(ctx, bson.M{"hotelId": p.Pid}, p, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Error().Msgf("Failed to upsert hotel [id: %v] location: %v", p.Pid, err)
+		return nil, status.Errorf(codes.Internal, "failed to store hotel %s location: %v", p.Pid, err)
+	}
+
+	s.mu.Lock()
+	s.index.Add(p)
+	s.mu.Unlock()
+	s.markSnapshot()
+
+	return &pb.UpdateResult{Existed: updateRes.MatchedCount > 0}, nil
+}
+
+// RemoveHotel deletes a hotel location and removes the hotel from the index.
+func (s *Server) RemoveHotel(ctx context.Context, req *pb.RemoveRequest) (*pb.UpdateResult, error) {
+	log.Trace().Msgf("In geo RemoveHotel, hotelId = %s", req.HotelId)
+
+	if req.HotelId == "" {
+		return nil, status.Error(codes.InvalidArgument, "hotelId must be set")
+	}
+
+	collection := s.MongoClient.Database("geo-db").Collection("geo")
+	deleteRes, err := collection.DeleteMany(ctx, bson.M{"hotelId": req.HotelId})
+	if err != nil {
+		log.Error().Msgf("Failed to remove hotel [id: %v] location: %v", req.HotelId, err)
+		return nil, status.Errorf(codes.Internal, "failed to remove hotel %s location: %v", req.HotelId, err)
+	}
+
+	s.mu.Lock()
+	s.index.Remove(req.HotelId)
+	s.mu.Unlock()
+	s.markSnapshot()
+
+	return &pb.UpdateResult{Existed: deleteRes.DeletedCount > 0}, nil
+}
+
 // newGeoIndex returns a geo index with points loaded
-func newGeoIndex(client *mongo.Client) *geoindex.ClusteringIndex {
+func newGeoIndex(client *mongo.Client) *snapshot.Index {
 	log.Trace().Msg("new geo newGeoIndex")
 
+	points, err := loadPoints(context.TODO(), client)
+	if err != nil {
+		log.Error().Msgf("Failed get geo data: %v", err)
+	}
+
+	return buildGeoIndex(points)
+}
+
+// loadPoints reads all hotel locations from geo-db.geo.
+func loadPoints(ctx context.Context, client *mongo.Client) ([]*point, error) {
 	collection := client.Database("geo-db").Collection("geo")
-	curr, err := collection.Find(context.TODO(), bson.D{})
+	curr, err := collection.Find(ctx, bson.D{})
 	if err != nil {
-		log.Error().Msgf("Failed get geo data: ", err)
+		return nil, err
 	}
 
 	var points []*point
-	curr.All(context.TODO(), &points)
-	if err != nil {
-		log.Error().Msgf("Failed get geo data: ", err)
+	if err := curr.All(ctx, &points); err != nil {
+		return nil, err
 	}
 
-	// add points to index
-	index := geoindex.NewClusteringIndex()
+	return points, nil
+}
+
+// buildGeoIndex returns a geo index holding points.
+func buildGeoIndex(points []*point) *snapshot.Index {
+	index := snapshot.NewIndex()
 	for _, point := range points {
 		index.Add(point)
 	}
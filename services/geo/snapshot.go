@@ -0,0 +1,101 @@
+package geo
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"hotelReservation/snapshot"
+)
+
+// loadIndex returns the index stored in the snapshot, or builds it from
+// geo-db.geo and saves a new snapshot when that one is missing, stale or
+// does not hold the hotels of geo-db.geo.
+func (s *Server) loadIndex() *snapshot.Index {
+	if s.SnapshotPath != "" {
+		start := time.Now()
+		index, err := snapshot.Load(s.SnapshotPath, s.SnapshotMaxAge)
+		if err == nil {
+			err = s.checkSnapshot(context.TODO(), index)
+		}
+		if err == nil {
+			log.Info().Msgf("Loaded geo index snapshot %s with %d points in %v", s.SnapshotPath, index.Len(), time.Since(start))
+			return index
+		}
+		log.Info().Msgf("Geo index snapshot not loaded, reading geo-db: %v", err)
+	}
+
+	index := newGeoIndex(s.MongoClient)
+	if s.SnapshotPath != "" {
+		if err := index.Save(s.SnapshotPath); err != nil {
+			log.Error().Msgf("Failed to save geo index snapshot %s: %v", s.SnapshotPath, err)
+		}
+	}
+
+	return index
+}
+
+// checkSnapshot returns an error unless index holds the hotels of geo-db.geo,
+// which may have changed while the service was down.
+func (s *Server) checkSnapshot(ctx context.Context, index *snapshot.Index) error {
+	values, err := s.MongoClient.Database("geo-db").Collection("geo").Distinct(ctx, "hotelId", bson.D{})
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return index.Check(ids)
+}
+
+// snapshotFlushInterval is how often a changed index is written to its
+// snapshot. Updates only mark the index as changed, so that they do not
+// wait for a write of the whole index.
+const snapshotFlushInterval = 10 * time.Second
+
+// markSnapshot records that the index changed since the last snapshot.
+func (s *Server) markSnapshot() {
+	s.snapDirty.Store(true)
+}
+
+// flushSnapshots writes the changed index every snapshotFlushInterval until
+// ctx is done.
+func (s *Server) flushSnapshots(ctx context.Context) {
+	ticker := time.NewTicker(snapshotFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.flushSnapshot()
+		}
+	}
+}
+
+// flushSnapshot writes the current index to SnapshotPath if it changed
+// since the last snapshot.
+func (s *Server) flushSnapshot() {
+	if s.SnapshotPath == "" {
+		return
+	}
+
+	s.snapMu.Lock()
+	defer s.snapMu.Unlock()
+	if !s.snapDirty.Swap(false) {
+		return
+	}
+
+	s.mu.RLock()
+	err := s.index.Save(s.SnapshotPath)
+	s.mu.RUnlock()
+	if err != nil {
+		log.Error().Msgf("Failed to save geo index snapshot %s: %v", s.SnapshotPath, err)
+		s.snapDirty.Store(true)
+	}
+}
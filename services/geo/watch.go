@@ -0,0 +1,51 @@
+package geo
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"hotelReservation/mongowatch"
+)
+
+// watchIndex keeps the index in sync with geo-db.geo.
+func (s *Server) watchIndex(ctx context.Context) {
+	w := &mongowatch.Watcher{
+		Collection:   s.MongoClient.Database("geo-db").Collection("geo"),
+		PollInterval: s.PollInterval,
+		Apply:        s.applyChange,
+		Reload:       s.reloadIndex,
+	}
+	w.Run(ctx)
+}
+
+// applyChange adds an inserted or updated hotel location to the index.
+func (s *Server) applyChange(_ context.Context, doc bson.Raw) error {
+	p := new(point)
+	if err := bson.Unmarshal(doc, p); err != nil {
+		return err
+	}
+	log.Trace().Msgf("geo change, hotelId = %s", p.Pid)
+	s.mu.Lock()
+	s.index.Add(p)
+	s.mu.Unlock()
+	s.markSnapshot()
+	return nil
+}
+
+// reloadIndex rebuilds the index from MongoDB and swaps it in.
+func (s *Server) reloadIndex(ctx context.Context) error {
+	points, err := loadPoints(ctx, s.MongoClient)
+	if err != nil {
+		return err
+	}
+	index := buildGeoIndex(points)
+
+	s.mu.Lock()
+	s.index = index
+	s.mu.Unlock()
+	s.markSnapshot()
+
+	log.Trace().Msgf("geo index reloaded, points = %d", len(points))
+	return nil
+}
@@ -0,0 +1,102 @@
+package profile
+
+import (
+	"strings"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// masksKey holds the keys of the field masks profiles are cached
+	// under, comma separated, so that a write on any server invalidates
+	// the profile under each of them.
+	masksKey = "profile_masks"
+	// maxCachedMasks bounds the masks profiles are cached under, as a
+	// write deletes one key per mask and locale. Profiles read with other
+	// masks are not cached.
+	maxCachedMasks = 16
+	// maskedProfileTTL is how long, in seconds, profiles read with a mask
+	// stay cached, in case an evicted masksKey hides their mask from a
+	// write.
+	maskedProfileTTL = 300
+)
+
+// profileKey returns the memcached key of a hotel profile in a locale, read
+// with the mask named mask, or whole for an empty one.
+func profileKey(hotelId, locale, mask string) string {
+	if mask == "" {
+		return memcKey(hotelId, locale)
+	}
+	return memcKey(hotelId, locale) + "_" + mask
+}
+
+// cacheMask reports whether profiles read with the mask named mask may be
+// cached, registering it in masksKey first if needed.
+func (s *Server) cacheMask(mask string) bool {
+	if mask == "" {
+		return true
+	}
+	if _, ok := s.masks.Load(mask); ok {
+		return true
+	}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		item, err := s.MemcClient.Get(masksKey)
+		if err == memcache.ErrCacheMiss {
+			err = s.MemcClient.Add(&memcache.Item{Key: masksKey, Value: []byte(mask)})
+		} else if err == nil {
+			masks := strings.Split(string(item.Value), ",")
+			for _, m := range masks {
+				if m == mask {
+					s.masks.Store(mask, true)
+					return true
+				}
+			}
+			if len(masks) >= maxCachedMasks {
+				return false
+			}
+			item.Value = append(item.Value, ","+mask...)
+			err = s.MemcClient.CompareAndSwap(item)
+		}
+
+		switch err {
+		case nil:
+			s.masks.Store(mask, true)
+			return true
+		case memcache.ErrNotStored, memcache.ErrCASConflict:
+			// another server changed the list, read it again
+		default:
+			log.Error().Msgf("Failed to register profile mask %v: %v", mask, err)
+			return false
+		}
+	}
+	return false
+}
+
+// cachedMasks returns the masks profiles may be cached under, besides whole
+// profiles.
+func (s *Server) cachedMasks() []string {
+	seen := make(map[string]bool)
+	item, err := s.MemcClient.Get(masksKey)
+	if err == nil {
+		for _, m := range strings.Split(string(item.Value), ",") {
+			seen[m] = true
+		}
+	} else if err != memcache.ErrCacheMiss {
+		log.Error().Msgf("Failed to get profile masks: %v", err)
+	}
+	// masks this server registered, in case the list was evicted
+	s.masks.Range(func(m, _ interface{}) bool {
+		seen[m.(string)] = true
+		return true
+	})
+
+	masks := make([]string, 0, len(seen))
+	for m := range seen {
+		if m != "" {
+			masks = append(masks, m)
+		}
+	}
+	return masks
+}
@@ -0,0 +1,297 @@
+package profile
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	pb "hotelReservation/services/profile/proto"
+)
+
+// Hotel is a hotel profile as stored in profile-db.hotels.
+type Hotel struct {
+	Id          string   `bson:"id"`
+	Name        string   `bson:"name"`
+	PhoneNumber string   `bson:"phoneNumber"`
+	Description string   `bson:"description"`
+	Address     *Address `bson:"address"`
+	Images      []*Image `bson:"images,omitempty"`
+	Version     int64    `bson:"version"`
+
+	Amenities    []string    `bson:"amenities,omitempty"`
+	StarRating   float32     `bson:"starRating,omitempty"`
+	CheckInTime  string      `bson:"checkInTime,omitempty"`
+	CheckOutTime string      `bson:"checkOutTime,omitempty"`
+	RoomTypes    []*RoomType `bson:"roomTypes,omitempty"`
+}
+
+type Address struct {
+	StreetNumber string  `bson:"streetNumber"`
+	StreetName   string  `bson:"streetName"`
+	City         string  `bson:"city"`
+	State        string  `bson:"state"`
+	Country      string  `bson:"country"`
+	PostalCode   string  `bson:"postalCode"`
+	Lat          float32 `bson:"lat"`
+	Lon          float32 `bson:"lon"`
+}
+
+type Image struct {
+	Url     string `bson:"url"`
+	Default bool   `bson:"default"`
+
+	// Uploaded images keep their metadata and the hotel they show.
+	Id           string `bson:"id,omitempty"`
+	ThumbnailUrl string `bson:"thumbnailUrl,omitempty"`
+	ContentType  string `bson:"contentType,omitempty"`
+	Width        int32  `bson:"width,omitempty"`
+	Height       int32  `bson:"height,omitempty"`
+	Size         int64  `bson:"size,omitempty"`
+	HotelId      string `bson:"hotelId,omitempty"`
+	UploadedBy   string `bson:"uploadedBy,omitempty"`
+}
+
+type RoomType struct {
+	Code         string `bson:"code"`
+	Name         string `bson:"name"`
+	Description  string `bson:"description"`
+	BedType      string `bson:"bedType"`
+	MaxOccupancy int32  `bson:"maxOccupancy"`
+}
+
+// amenities are the facilities a hotel profile may list.
+var amenities = map[string]bool{
+	"wifi":                  true,
+	"pool":                  true,
+	"parking":               true,
+	"ev_charging":           true,
+	"fitness_center":        true,
+	"spa":                   true,
+	"restaurant":            true,
+	"bar":                   true,
+	"room_service":          true,
+	"airport_shuttle":       true,
+	"pet_friendly":          true,
+	"air_conditioning":      true,
+	"business_center":       true,
+	"laundry":               true,
+	"wheelchair_accessible": true,
+	"accessible_bathroom":   true,
+	"elevator":              true,
+	"hearing_accessible":    true,
+}
+
+// hotelFields are the top-level fields an empty update mask replaces.
+var hotelFields = []string{
+	"name", "phoneNumber", "description", "address", "images",
+	"amenities", "starRating", "checkInTime", "checkOutTime", "roomTypes",
+}
+
+// merge copies the field named by an update mask path from src to h. Paths
+// use the proto field names, which are also the document field names.
+func (h *Hotel) merge(src *Hotel, path string) error {
+	if strings.HasPrefix(path, "address.") {
+		if src.Address == nil {
+			return fmt.Errorf("address must be set to update %q", path)
+		}
+		if h.Address == nil {
+			h.Address = new(Address)
+		}
+	}
+	switch path {
+	case "name":
+		h.Name = src.Name
+	case "phoneNumber":
+		h.PhoneNumber = src.PhoneNumber
+	case "description":
+		h.Description = src.Description
+	case "images":
+		h.Images = src.Images
+	case "address":
+		h.Address = src.Address
+	case "amenities":
+		h.Amenities = src.Amenities
+	case "starRating":
+		h.StarRating = src.StarRating
+	case "checkInTime":
+		h.CheckInTime = src.CheckInTime
+	case "checkOutTime":
+		h.CheckOutTime = src.CheckOutTime
+	case "roomTypes":
+		h.RoomTypes = src.RoomTypes
+	case "address.streetNumber":
+		h.Address.StreetNumber = src.Address.StreetNumber
+	case "address.streetName":
+		h.Address.StreetName = src.Address.StreetName
+	case "address.city":
+		h.Address.City = src.Address.City
+	case "address.state":
+		h.Address.State = src.Address.State
+	case "address.country":
+		h.Address.Country = src.Address.Country
+	case "address.postalCode":
+		h.Address.PostalCode = src.Address.PostalCode
+	case "address.lat":
+		h.Address.Lat = src.Address.Lat
+	case "address.lon":
+		h.Address.Lon = src.Address.Lon
+	default:
+		return fmt.Errorf("unknown update mask path %q", path)
+	}
+	return nil
+}
+
+// validate checks that a profile has a name and a complete address with
+// coordinates on the globe, and that its details are well formed.
+func (h *Hotel) validate() error {
+	if strings.TrimSpace(h.Id) == "" {
+		return fmt.Errorf("hotel id must be set")
+	}
+	if strings.TrimSpace(h.Name) == "" {
+		return fmt.Errorf("hotel name must be set")
+	}
+	a := h.Address
+	if a == nil {
+		return fmt.Errorf("hotel address must be set")
+	}
+	for field, value := range map[string]string{
+		"streetNumber": a.StreetNumber,
+		"streetName":   a.StreetName,
+		"city":         a.City,
+		"country":      a.Country,
+	} {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("address %s must be set", field)
+		}
+	}
+	if math.IsNaN(float64(a.Lat)) || a.Lat < -90 || a.Lat > 90 {
+		return fmt.Errorf("address lat %v is out of range [-90, 90]", a.Lat)
+	}
+	if math.IsNaN(float64(a.Lon)) || a.Lon < -180 || a.Lon > 180 {
+		return fmt.Errorf("address lon %v is out of range [-180, 180]", a.Lon)
+	}
+
+	for _, amenity := range h.Amenities {
+		if !amenities[amenity] {
+			return fmt.Errorf("unknown amenity %q", amenity)
+		}
+	}
+	if h.StarRating != 0 && (h.StarRating < 1 || h.StarRating > 5 || h.StarRating*2 != float32(int(h.StarRating*2))) {
+		return fmt.Errorf("star rating %v must be 1 to 5 in half stars", h.StarRating)
+	}
+	for field, value := range map[string]string{"checkInTime": h.CheckInTime, "checkOutTime": h.CheckOutTime} {
+		if _, err := time.Parse("15:04", value); value != "" && err != nil {
+			return fmt.Errorf("%s %q must be in HH:MM form", field, value)
+		}
+	}
+	seen := make(map[string]bool, len(h.RoomTypes))
+	for _, r := range h.RoomTypes {
+		if r == nil || strings.TrimSpace(r.Code) == "" || strings.TrimSpace(r.Name) == "" {
+			return fmt.Errorf("room types must have a code and a name")
+		}
+		if seen[r.Code] {
+			return fmt.Errorf("duplicate room type %q", r.Code)
+		}
+		seen[r.Code] = true
+		if r.MaxOccupancy < 1 {
+			return fmt.Errorf("room type %q must sleep at least one guest", r.Code)
+		}
+	}
+	return nil
+}
+
+func hotelFromProto(p *pb.Hotel) *Hotel {
+	h := &Hotel{
+		Id:          p.Id,
+		Name:        p.Name,
+		PhoneNumber: p.PhoneNumber,
+		Description: p.Description,
+		Version:     p.Version,
+
+		Amenities:    p.Amenities,
+		StarRating:   p.StarRating,
+		CheckInTime:  p.CheckInTime,
+		CheckOutTime: p.CheckOutTime,
+	}
+	if a := p.Address; a != nil {
+		h.Address = &Address{a.StreetNumber, a.StreetName, a.City, a.State, a.Country, a.PostalCode, a.Lat, a.Lon}
+	}
+	for _, img := range p.Images {
+		h.Images = append(h.Images, imageFromProto(img))
+	}
+	for _, r := range p.RoomTypes {
+		h.RoomTypes = append(h.RoomTypes, &RoomType{r.Code, r.Name, r.Description, r.BedType, r.MaxOccupancy})
+	}
+	return h
+}
+
+func (h *Hotel) toProto() *pb.Hotel {
+	p := &pb.Hotel{
+		Id:          h.Id,
+		Name:        h.Name,
+		PhoneNumber: h.PhoneNumber,
+		Description: h.Description,
+		Version:     h.Version,
+
+		Amenities:    h.Amenities,
+		StarRating:   h.StarRating,
+		CheckInTime:  h.CheckInTime,
+		CheckOutTime: h.CheckOutTime,
+	}
+	if a := h.Address; a != nil {
+		p.Address = &pb.Address{
+			StreetNumber: a.StreetNumber,
+			StreetName:   a.StreetName,
+			City:         a.City,
+			State:        a.State,
+			Country:      a.Country,
+			PostalCode:   a.PostalCode,
+			Lat:          a.Lat,
+			Lon:          a.Lon,
+		}
+	}
+	for _, img := range h.Images {
+		p.Images = append(p.Images, img.toProto())
+	}
+	for _, r := range h.RoomTypes {
+		p.RoomTypes = append(p.RoomTypes, &pb.RoomType{
+			Code:         r.Code,
+			Name:         r.Name,
+			Description:  r.Description,
+			BedType:      r.BedType,
+			MaxOccupancy: r.MaxOccupancy,
+		})
+	}
+	return p
+}
+
+func imageFromProto(p *pb.Image) *Image {
+	return &Image{
+		Url:          p.Url,
+		Default:      p.Default,
+		Id:           p.Id,
+		ThumbnailUrl: p.ThumbnailUrl,
+		ContentType:  p.ContentType,
+		Width:        p.Width,
+		Height:       p.Height,
+		Size:         p.Size,
+		HotelId:      p.HotelId,
+		UploadedBy:   p.UploadedBy,
+	}
+}
+
+func (img *Image) toProto() *pb.Image {
+	return &pb.Image{
+		Url:          img.Url,
+		Default:      img.Default,
+		Id:           img.Id,
+		ThumbnailUrl: img.ThumbnailUrl,
+		ContentType:  img.ContentType,
+		Width:        img.Width,
+		Height:       img.Height,
+		Size:         img.Size,
+		HotelId:      img.HotelId,
+		UploadedBy:   img.UploadedBy,
+	}
+}
@@ -0,0 +1,87 @@
+package profile
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultLocale is the locale of the name and description stored with
+// each hotel in profile-db.hotels.
+const defaultLocale = "en"
+
+// canonicalLocale returns a BCP 47 style tag with a lower-case language and
+// upper-case region, e.g. "fr_ca" becomes "fr-CA". Empty or malformed tags
+// yield defaultLocale.
+func canonicalLocale(locale string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	for i, part := range parts {
+		if part == "" || len(part) > 8 || strings.IndexFunc(part, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+		}) >= 0 {
+			return defaultLocale
+		}
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// localeChain returns the locales to try for a canonical locale, from the
+// most to the least specific, ending with defaultLocale. For example
+// "fr-CA" yields ["fr-CA", "fr", "en"].
+func localeChain(locale string) []string {
+	var chain []string
+	for tag := locale; tag != ""; {
+		chain = append(chain, tag)
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	if chain[len(chain)-1] != defaultLocale {
+		chain = append(chain, defaultLocale)
+	}
+	return chain
+}
+
+// loadLocales returns the locales that have translations in
+// profile-db.locales, plus defaultLocale.
+func (s *Server) loadLocales() map[string]bool {
+	locales := map[string]bool{defaultLocale: true}
+	collection := s.MongoClient.Database("profile-db").Collection("locales")
+	values, err := collection.Distinct(context.TODO(), "locale", struct{}{})
+	if err != nil {
+		log.Error().Msgf("Failed get translated locales: %v", err)
+		return locales
+	}
+	for _, v := range values {
+		if locale, ok := v.(string); ok {
+			locales[canonicalLocale(locale)] = true
+		}
+	}
+	return locales
+}
+
+// cacheLocale returns the most specific locale in the fallback chain of
+// locale that has translations. Profiles are cached under this locale only,
+// so requests that resolve to the same translations share cache entries and
+// a write knows every key to invalidate.
+func (s *Server) cacheLocale(locale string) string {
+	for _, tag := range localeChain(locale) {
+		if s.locales[tag] {
+			return tag
+		}
+	}
+	return defaultLocale
+}
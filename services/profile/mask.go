@@ -0,0 +1,121 @@
+package profile
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	pb "hotelReservation/services/profile/proto"
+)
+
+// fieldTree is a field mask as a tree of field names. A leaf keeps the
+// whole field.
+type fieldTree map[string]fieldTree
+
+// newFieldTree checks the paths of mask against the Hotel message and
+// returns them as a tree, or nil if the mask is empty.
+func newFieldTree(mask *fieldmaskpb.FieldMask) (fieldTree, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+	if !mask.IsValid(&pb.Hotel{}) {
+		return nil, fmt.Errorf("invalid field mask %v", mask.GetPaths())
+	}
+
+	tree := fieldTree{"id": {}}
+	for _, path := range mask.GetPaths() {
+		node := tree
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			child, ok := node[name]
+			if ok && len(child) == 0 {
+				// a shorter path already keeps the whole field
+				break
+			}
+			if !ok {
+				child = fieldTree{}
+				node[name] = child
+			}
+			if i == len(names)-1 {
+				for k := range child {
+					delete(child, k)
+				}
+			}
+			node = child
+		}
+	}
+	return tree, nil
+}
+
+// prune clears the fields of hotel that are not in the tree.
+func (t fieldTree) prune(hotel *pb.Hotel) *pb.Hotel {
+	if t == nil || hotel == nil {
+		return hotel
+	}
+	t.pruneMessage(hotel.ProtoReflect())
+	return hotel
+}
+
+func (t fieldTree) pruneMessage(m protoreflect.Message) {
+	var clear []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		child, ok := t[string(fd.Name())]
+		switch {
+		case !ok:
+			clear = append(clear, fd)
+		case len(child) > 0 && fd.Message() != nil && !fd.IsList() && !fd.IsMap():
+			child.pruneMessage(v.Message())
+		}
+		return true
+	})
+	for _, fd := range clear {
+		m.Clear(fd)
+	}
+}
+
+// paths returns the leaves of the tree as sorted dotted paths.
+func (t fieldTree) paths() []string {
+	var paths []string
+	var walk func(prefix string, node fieldTree)
+	walk = func(prefix string, node fieldTree) {
+		for name, child := range node {
+			if len(child) == 0 {
+				paths = append(paths, prefix+name)
+			} else {
+				walk(prefix+name+".", child)
+			}
+		}
+	}
+	walk("", t)
+	sort.Strings(paths)
+	return paths
+}
+
+// projection returns the mongo projection reading only the fields of the
+// tree, or nil for whole documents. The profile-db field names are those of
+// the Hotel message.
+func (t fieldTree) projection() bson.M {
+	if t == nil {
+		return nil
+	}
+	projection := bson.M{}
+	for _, path := range t.paths() {
+		projection[path] = 1
+	}
+	return projection
+}
+
+// key names the tree in memcached keys, or is empty for whole profiles.
+func (t fieldTree) key() string {
+	if t == nil {
+		return ""
+	}
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(t.paths(), ",")))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
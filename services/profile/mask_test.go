@@ -0,0 +1,169 @@
+package profile
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	pb "hotelReservation/services/profile/proto"
+)
+
+// searchPaths matches the mask the frontend sends for /hotels.
+var searchPaths = []string{
+	"address.lat", "address.lon",
+	"name", "phoneNumber", "amenities", "starRating", "checkInTime", "checkOutTime", "roomTypes",
+}
+
+// benchHotels is the number of hotels of a /hotels response.
+const benchHotels = 80
+
+func TestFieldTreeProjection(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  bson.M
+	}{
+		{nil, nil},
+		{[]string{"name"}, bson.M{"id": 1, "name": 1}},
+		{[]string{"address.lat", "address.lon"}, bson.M{"id": 1, "address.lat": 1, "address.lon": 1}},
+		{[]string{"address.lat", "address"}, bson.M{"id": 1, "address": 1}},
+		{[]string{"roomTypes", "name"}, bson.M{"id": 1, "name": 1, "roomTypes": 1}},
+	}
+	for _, tt := range tests {
+		tree, err := newFieldTree(&fieldmaskpb.FieldMask{Paths: tt.paths})
+		if err != nil {
+			t.Fatalf("newFieldTree(%v): %v", tt.paths, err)
+		}
+		if got := tree.projection(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("projection of %v = %v, want %v", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestFieldTreeKey(t *testing.T) {
+	a, _ := newFieldTree(&fieldmaskpb.FieldMask{Paths: []string{"name", "address.lat"}})
+	b, _ := newFieldTree(&fieldmaskpb.FieldMask{Paths: []string{"address.lat", "name", "name"}})
+	c, _ := newFieldTree(&fieldmaskpb.FieldMask{Paths: []string{"name"}})
+	if a.key() != b.key() {
+		t.Errorf("equal masks have keys %q and %q", a.key(), b.key())
+	}
+	if a.key() == c.key() {
+		t.Errorf("different masks share key %q", a.key())
+	}
+	if k := fieldTree(nil).key(); k != "" {
+		t.Errorf("key of whole profiles = %q, want empty", k)
+	}
+}
+
+// benchHotel returns a profile the size of a seeded one.
+func benchHotel(id string) *pb.Hotel {
+	return &pb.Hotel{
+		Id:          id,
+		Name:        "St. Regis San Francisco",
+		PhoneNumber: "(415) 284-40" + id,
+		Description: "St. Regis Museum Tower is a 42-story, 484 ft skyscraper in the South of Market district of San Francisco, California, adjacent to Yerba Buena Gardens, Moscone Center, PacBell Building and the San Francisco Museum of Modern Art.",
+		Address: &pb.Address{
+			StreetNumber: "125",
+			StreetName:   "3rd St",
+			City:         "San Francisco",
+			State:        "CA",
+			Country:      "United States",
+			PostalCode:   "94109",
+			Lat:          37.7835,
+			Lon:          -122.41,
+		},
+		Images: []*pb.Image{
+			{Url: "https://images.example.com/hotels/" + id + "/lobby.jpg", Default: true},
+			{Url: "https://images.example.com/hotels/" + id + "/room.jpg"},
+		},
+		Version:      1,
+		Amenities:    []string{"wifi", "air_conditioning", "elevator", "parking", "wheelchair_accessible"},
+		StarRating:   4,
+		CheckInTime:  "15:00",
+		CheckOutTime: "11:00",
+		RoomTypes: []*pb.RoomType{
+			{Code: "KNG", Name: "King Room", Description: "City-view room with a king bed, work desk and rain shower.", BedType: "1 King", MaxOccupancy: 2},
+			{Code: "DQQ", Name: "Double Queen Room", Description: "Room with two queen beds, sofa and a bathtub.", BedType: "2 Queen", MaxOccupancy: 4},
+		},
+	}
+}
+
+// benchResult returns a response of benchHotels profiles with the fields in
+// paths, or whole ones for no paths.
+func benchResult(b *testing.B, paths []string) *pb.Result {
+	tree, err := newFieldTree(&fieldmaskpb.FieldMask{Paths: paths})
+	if err != nil {
+		b.Fatal(err)
+	}
+	res := &pb.Result{}
+	for i := 1; i <= benchHotels; i++ {
+		res.Hotels = append(res.Hotels, tree.prune(benchHotel(strconv.Itoa(i))))
+	}
+	return res
+}
+
+func benchmarkCodec(b *testing.B, res *pb.Result) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := proto.Marshal(res)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := proto.Unmarshal(data, new(pb.Result)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(proto.Size(res)), "B/resp")
+}
+
+// BenchmarkEncodeDecodeFull and BenchmarkEncodeDecodePartial compare the
+// size and the encoding of /hotels responses with whole profiles and with
+// the search result fields only.
+func BenchmarkEncodeDecodeFull(b *testing.B) {
+	benchmarkCodec(b, benchResult(b, nil))
+}
+
+func BenchmarkEncodeDecodePartial(b *testing.B) {
+	benchmarkCodec(b, benchResult(b, searchPaths))
+}
+
+func benchmarkGetProfiles(b *testing.B, mask *fieldmaskpb.FieldMask) {
+	addr := os.Getenv("PROFILE_BENCH_ADDR")
+	if addr == "" {
+		b.Skip("PROFILE_BENCH_ADDR not set")
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewProfileClient(conn)
+
+	req := benchRequest()
+	req.Fields = mask
+	var size int
+	for i := 0; i < b.N; i++ {
+		res, err := client.GetProfiles(context.Background(), req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = proto.Size(res)
+	}
+	b.ReportMetric(float64(size), "B/resp")
+}
+
+// BenchmarkGetProfilesFull and BenchmarkGetProfilesPartial call the profile
+// service at PROFILE_BENCH_ADDR, like localhost:8081.
+func BenchmarkGetProfilesFull(b *testing.B) {
+	benchmarkGetProfiles(b, nil)
+}
+
+func BenchmarkGetProfilesPartial(b *testing.B) {
+	benchmarkGetProfiles(b, &fieldmaskpb.FieldMask{Paths: searchPaths})
+}
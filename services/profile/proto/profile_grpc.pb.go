@@ -19,7 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Profile_GetProfiles_FullMethodName = "/profile.Profile/GetProfiles"
+	Profile_GetProfiles_FullMethodName   = "/profile.Profile/GetProfiles"
+	Profile_CreateProfile_FullMethodName = "/profile.Profile/CreateProfile"
+	Profile_UpdateProfile_FullMethodName = "/profile.Profile/UpdateProfile"
+	Profile_DeleteProfile_FullMethodName = "/profile.Profile/DeleteProfile"
+	Profile_AddImages_FullMethodName     = "/profile.Profile/AddImages"
 )
 
 // ProfileClient is the client API for Profile service.
@@ -27,6 +31,14 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ProfileClient interface {
 	GetProfiles(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// Adds a new hotel profile. The hotel id must not be in use.
+	CreateProfile(ctx context.Context, in *Hotel, opts ...grpc.CallOption) (*Hotel, error)
+	// Changes the fields of a hotel profile listed in the update mask.
+	UpdateProfile(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Hotel, error)
+	// Removes a hotel profile and its translations.
+	DeleteProfile(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResult, error)
+	// Adds uploaded images to a hotel profile.
+	AddImages(ctx context.Context, in *AddImagesRequest, opts ...grpc.CallOption) (*Hotel, error)
 }
 
 type profileClient struct {
@@ -46,11 +58,55 @@ func (c *profileClient) GetProfiles(ctx context.Context, in *Request, opts ...gr
 	return out, nil
 }
 
+func (c *profileClient) CreateProfile(ctx context.Context, in *Hotel, opts ...grpc.CallOption) (*Hotel, error) {
+	out := new(Hotel)
+	err := c.cc.Invoke(ctx, Profile_CreateProfile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileClient) UpdateProfile(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Hotel, error) {
+	out := new(Hotel)
+	err := c.cc.Invoke(ctx, Profile_UpdateProfile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileClient) DeleteProfile(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResult, error) {
+	out := new(DeleteResult)
+	err := c.cc.Invoke(ctx, Profile_DeleteProfile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *profileClient) AddImages(ctx context.Context, in *AddImagesRequest, opts ...grpc.CallOption) (*Hotel, error) {
+	out := new(Hotel)
+	err := c.cc.Invoke(ctx, Profile_AddImages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProfileServer is the server API for Profile service.
 // All implementations must embed UnimplementedProfileServer
 // for forward compatibility
 type ProfileServer interface {
 	GetProfiles(context.Context, *Request) (*Result, error)
+	// Adds a new hotel profile. The hotel id must not be in use.
+	CreateProfile(context.Context, *Hotel) (*Hotel, error)
+	// Changes the fields of a hotel profile listed in the update mask.
+	UpdateProfile(context.Context, *UpdateRequest) (*Hotel, error)
+	// Removes a hotel profile and its translations.
+	DeleteProfile(context.Context, *DeleteRequest) (*DeleteResult, error)
+	// Adds uploaded images to a hotel profile.
+	AddImages(context.Context, *AddImagesRequest) (*Hotel, error)
 	mustEmbedUnimplementedProfileServer()
 }
 
@@ -61,6 +117,18 @@ type UnimplementedProfileServer struct {
 func (UnimplementedProfileServer) GetProfiles(context.Context, *Request) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetProfiles not implemented")
 }
+func (UnimplementedProfileServer) CreateProfile(context.Context, *Hotel) (*Hotel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProfile not implemented")
+}
+func (UnimplementedProfileServer) UpdateProfile(context.Context, *UpdateRequest) (*Hotel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+}
+func (UnimplementedProfileServer) DeleteProfile(context.Context, *DeleteRequest) (*DeleteResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProfile not implemented")
+}
+func (UnimplementedProfileServer) AddImages(context.Context, *AddImagesRequest) (*Hotel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddImages not implemented")
+}
 func (UnimplementedProfileServer) mustEmbedUnimplementedProfileServer() {}
 
 // UnsafeProfileServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +160,78 @@ func _Profile_GetProfiles_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Profile_CreateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Hotel)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServer).CreateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Profile_CreateProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServer).CreateProfile(ctx, req.(*Hotel))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Profile_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Profile_UpdateProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServer).UpdateProfile(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Profile_DeleteProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServer).DeleteProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Profile_DeleteProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServer).DeleteProfile(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Profile_AddImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServer).AddImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Profile_AddImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServer).AddImages(ctx, req.(*AddImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Profile_ServiceDesc is the grpc.ServiceDesc for Profile service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +243,22 @@ var Profile_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetProfiles",
 			Handler:    _Profile_GetProfiles_Handler,
 		},
+		{
+			MethodName: "CreateProfile",
+			Handler:    _Profile_CreateProfile_Handler,
+		},
+		{
+			MethodName: "UpdateProfile",
+			Handler:    _Profile_UpdateProfile_Handler,
+		},
+		{
+			MethodName: "DeleteProfile",
+			Handler:    _Profile_DeleteProfile_Handler,
+		},
+		{
+			MethodName: "AddImages",
+			Handler:    _Profile_AddImages_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/profile/proto/profile.proto",
@@ -13,14 +13,19 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
 	"hotelReservation/registry"
 	pb "hotelReservation/services/profile/proto"
 	"hotelReservation/tls"
+	"hotelReservation/tune"
 )
 
 const name = "srv-profile"
@@ -29,7 +34,10 @@ const name = "srv-profile"
 type Server struct {
 	pb.UnimplementedProfileServer
 
-	uuid string
+	uuid    string
+	locales map[string]bool
+	// masks holds the masks this server cached profiles under.
+	masks sync.Map
 
 	Tracer         trace.Tracer
 	TracerProvider trace.TracerProvider
@@ -38,6 +46,9 @@ type Server struct {
 	MongoClient    *mongo.Client
 	Registry       *registry.Client
 	MemcClient     *memcache.Client
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -48,6 +59,7 @@ func (s *Server) Run() error {
 	}
 
 	s.uuid = uuid.New().String()
+	s.locales = s.loadLocales()
 
 	log.Trace().Msgf("in run s.IpAddr = %s, port = %d", s.IpAddr, s.Port)
 	
@@ -59,6 +71,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			otelgrpc.StreamServerInterceptor(
@@ -112,73 +125,195 @@ func (s *Server) Shutdown() {
 	s.Registry.Deregister(s.uuid)
 }
 
-// GetProfiles returns hotel profiles for requested IDs
+// GetProfiles returns hotel profiles for requested IDs, with the name and
+// description in the requested locale when a translation exists. Unknown
+// hotel IDs are left out of the result.
 func (s *Server) GetProfiles(ctx context.Context, req *pb.Request) (*pb.Result, error) {
 	log.Trace().Msgf("In GetProfiles")
-	var wg sync.WaitGroup
-	var mutex sync.Mutex
+	locale := s.cacheLocale(canonicalLocale(req.Locale))
+	fields, err := newFieldTree(req.Fields)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// profiles read with a mask are cached apart from whole ones
+	mask := fields.key()
+	cacheable := s.cacheMask(mask)
+
 	// one hotel should only have one profile
-	hotelIds := make([]string, 0)
-	profileMap := make(map[string]struct{})
+	memcKeys := make([]string, 0, len(req.HotelIds))
+	profileMap := make(map[string]*pb.Hotel)
 	for _, hotelId := range req.HotelIds {
-		hotelIds = append(hotelIds, hotelId)
-		profileMap[hotelId] = struct{}{}
+		if _, ok := profileMap[hotelId]; !ok {
+			memcKeys = append(memcKeys, profileKey(hotelId, locale, mask))
+			profileMap[hotelId] = nil
+		}
 	}
 
 	// ctx, span := s.Tracer.Start(ctx, "memcached_get_profile", trace.WithSpanKind(trace.SpanKindClient))
-	resMap, err := s.MemcClient.GetMulti(hotelIds)
+	resMap, err := s.MemcClient.GetMulti(memcKeys)
 	// span.End()
+	if err != nil && err != memcache.ErrCacheMiss {
+		// serve from mongo when memcached is unavailable
+		log.Error().Msgf("Tried to get hotelIds [%v], but got memmcached error = %s", memcKeys, err)
+	}
+	for _, item := range resMap {
+		log.Trace().Msgf("memc hit with %v", string(item.Value))
 
-	res := new(pb.Result)
-	hotels := make([]*pb.Hotel, 0)
+		hotelProf := new(pb.Hotel)
+		if err := json.Unmarshal(item.Value, hotelProf); err != nil {
+			log.Error().Msgf("Failed to unmarshal cached profile [key: %v]: %v", item.Key, err)
+			continue
+		}
+		profileMap[hotelProf.Id] = hotelProf
+	}
 
-	if err != nil && err != memcache.ErrCacheMiss {
-		log.Panic().Msgf("Tried to get hotelIds [%v], but got memmcached error = %s", hotelIds, err)
-	} else {
-		for hotelId, item := range resMap {
-			profileStr := string(item.Value)
-			log.Trace().Msgf("memc hit with %v", profileStr)
-
-			hotelProf := new(pb.Hotel)
-			json.Unmarshal(item.Value, hotelProf)
-			hotels = append(hotels, hotelProf)
-			delete(profileMap, hotelId)
+	misses := make([]string, 0)
+	for hotelId, hotelProf := range profileMap {
+		if hotelProf == nil {
+			misses = append(misses, hotelId)
+		}
+	}
+	if len(misses) > 0 {
+		found, err := s.findProfiles(ctx, misses, locale, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		items := make([]*memcache.Item, 0, len(found))
+		for _, hotelProf := range found {
+			profileMap[hotelProf.Id] = hotelProf
+			profJson, err := json.Marshal(hotelProf)
+			if err != nil {
+				log.Error().Msgf("Failed to marshal hotel [id: %v]: %v", hotelProf.Id, err)
+				continue
+			}
+			item := &memcache.Item{Key: profileKey(hotelProf.Id, locale, mask), Value: profJson}
+			if mask != "" {
+				item.Expiration = maskedProfileTTL
+			}
+			items = append(items, item)
+		}
+		if !cacheable {
+			items = nil
+		}
+
+		// write to memcached before replying, bounded by the client timeout,
+		// so that a burst of misses does not pile up writers
+		if err := tune.SetMulti(s.MemcClient, items); err != nil {
+			log.Error().Msgf("Failed to cache hotel profiles: %v", err)
 		}
+	}
 
-		wg.Add(len(profileMap))
-		for hotelId := range profileMap {
-			go func(hotelId string) {
-				var hotelProf *pb.Hotel
+	// translations fill names and descriptions outside the mask, so trim
+	// hits and misses alike
+	res := &pb.Result{Hotels: make([]*pb.Hotel, 0, len(profileMap))}
+	for _, hotelId := range req.HotelIds {
+		if hotelProf := profileMap[hotelId]; hotelProf != nil {
+			res.Hotels = append(res.Hotels, fields.prune(hotelProf))
+			delete(profileMap, hotelId)
+		}
+	}
+	log.Trace().Msgf("In GetProfiles after getting resp")
+	return res, nil
+}
 
-				collection := s.MongoClient.Database("profile-db").Collection("hotels")
+// findProfiles reads the fields of the profiles of hotelIds from mongo in
+// one query and localizes them.
+func (s *Server) findProfiles(ctx context.Context, hotelIds []string, locale string, fields fieldTree) ([]*pb.Hotel, error) {
+	collection := s.MongoClient.Database("profile-db").Collection("hotels")
 
-				// _, span := s.Tracer.Start(ctx, "mongo_profile", trace.WithSpanKind(trace.SpanKindClient))
-				err := collection.FindOne(context.TODO(), bson.D{{"id", hotelId}}).Decode(&hotelProf)
-				// span.End()
+	// _, span := s.Tracer.Start(ctx, "mongo_profile", trace.WithSpanKind(trace.SpanKindClient))
+	opts := options.Find()
+	if projection := fields.projection(); projection != nil {
+		opts.SetProjection(projection)
+	}
+	curr, err := collection.Find(ctx, bson.M{"id": bson.M{"$in": hotelIds}}, opts)
+	// span.End()
+	if err != nil {
+		log.Error().Msgf("Failed get hotels data: %v", err)
+		return nil, mongoError(err)
+	}
+	var hotels []Hotel
+	if err := curr.All(ctx, &hotels); err != nil {
+		log.Error().Msgf("Failed decode hotels data: %v", err)
+		return nil, mongoError(err)
+	}
 
-				if err != nil {
-					log.Error().Msgf("Failed get hotels data: ", err)
-				}
+	profiles := make([]*pb.Hotel, len(hotels))
+	for i := range hotels {
+		profiles[i] = hotels[i].toProto()
+	}
+	if err := s.localize(ctx, profiles, locale); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
 
-				mutex.Lock()
-				hotels = append(hotels, hotelProf)
-				mutex.Unlock()
+// localize replaces the names and descriptions of hotels with the most
+// specific translations found in profile-db.locales for locale.
+func (s *Server) localize(ctx context.Context, hotels []*pb.Hotel, locale string) error {
+	chain := localeChain(locale)
+	if len(hotels) == 0 || len(chain) == 1 && chain[0] == defaultLocale {
+		return nil
+	}
 
-				profJson, err := json.Marshal(hotelProf)
-				if err != nil {
-					log.Error().Msgf("Failed to marshal hotel [id: %v] with err:", hotelProf.Id, err)
-				}
-				memcStr := string(profJson)
+	hotelIds := make([]string, len(hotels))
+	for i, hotel := range hotels {
+		hotelIds[i] = hotel.Id
+	}
+	collection := s.MongoClient.Database("profile-db").Collection("locales")
+	curr, err := collection.Find(ctx, bson.M{"hotelId": bson.M{"$in": hotelIds}, "locale": bson.M{"$in": chain}})
+	if err != nil {
+		log.Error().Msgf("Failed get locales of hotels [%v]: %v", hotelIds, err)
+		return mongoError(err)
+	}
+	var translations []Translation
+	if err := curr.All(ctx, &translations); err != nil {
+		log.Error().Msgf("Failed decode locales of hotels [%v]: %v", hotelIds, err)
+		return mongoError(err)
+	}
 
-				// write to memcached
-				go s.MemcClient.Set(&memcache.Item{Key: hotelId, Value: []byte(memcStr)})
-				defer wg.Done()
-			}(hotelId)
+	byLocale := make(map[string]Translation, len(translations))
+	for _, t := range translations {
+		byLocale[t.HotelId+"_"+t.Locale] = t
+	}
+	for _, hotel := range hotels {
+		// fill each field from the most specific translation that has it
+		nameSet, descSet := false, false
+		for _, tag := range chain {
+			t, ok := byLocale[hotel.Id+"_"+tag]
+			if !ok {
+				continue
+			}
+			if !nameSet && t.Name != "" {
+				hotel.Name, nameSet = t.Name, true
+			}
+			if !descSet && t.Description != "" {
+				hotel.Description, descSet = t.Description, true
+			}
 		}
 	}
-	wg.Wait()
+	return nil
+}
 
-	res.Hotels = hotels
-	log.Trace().Msgf("In GetProfiles after getting resp")
-	return res, nil
+// mongoError converts a mongo error to a gRPC status, keeping the
+// cancellation or deadline of the request context.
+func mongoError(err error) error {
+	if ctxErr := status.FromContextError(err); ctxErr.Code() != codes.Unknown {
+		return ctxErr.Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// memcKey returns the memcached key of a hotel profile in a locale.
+func memcKey(hotelId, locale string) string {
+	return hotelId + "_" + locale
+}
+
+// Translation is a localized name and description of a hotel.
+type Translation struct {
+	HotelId     string `bson:"hotelId"`
+	Locale      string `bson:"locale"`
+	Name        string `bson:"name"`
+	Description string `bson:"description"`
 }
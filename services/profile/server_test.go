@@ -0,0 +1,499 @@
+package profile
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/address"
+	"go.mongodb.org/mongo-driver/mongo/description"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/wiremessage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	pb "hotelReservation/services/profile/proto"
+	"hotelReservation/tune"
+)
+
+// missRate is the share of the requested profiles evicted from memcached
+// before each call of the miss benchmarks.
+const missRate = 0.9
+
+// benchServer returns a server on the profile MongoDB and memcached at
+// PROFILE_BENCH_MONGO and PROFILE_BENCH_MEMC, seeded by the profile
+// service, and a function evicting missRate of the profiles of req.
+func benchServer(b *testing.B, req *pb.Request) (*Server, func()) {
+	mongoAddr, memcAddr := os.Getenv("PROFILE_BENCH_MONGO"), os.Getenv("PROFILE_BENCH_MEMC")
+	if mongoAddr == "" || memcAddr == "" {
+		b.Skip("PROFILE_BENCH_MONGO and PROFILE_BENCH_MEMC not set")
+	}
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://"+mongoAddr))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { client.Disconnect(context.Background()) })
+	memc := tune.NewMemCClient2(memcAddr)
+
+	evict := func() {
+		for _, hotelId := range req.HotelIds {
+			if rand.Float64() < missRate {
+				memc.Delete(memcKey(hotelId, req.Locale))
+			}
+		}
+	}
+	return &Server{MongoClient: client, MemcClient: memc}, evict
+}
+
+func benchRequest() *pb.Request {
+	req := &pb.Request{Locale: "en"}
+	for i := 1; i <= benchHotels; i++ {
+		req.HotelIds = append(req.HotelIds, strconv.Itoa(i))
+	}
+	return req
+}
+
+// BenchmarkPerHotelMisses and BenchmarkBatchedMisses compare the cache miss
+// path of GetProfiles before and after misses were batched: one FindOne and
+// one memcached Set goroutine per missed hotel, against one $in query and a
+// single SetMulti.
+func BenchmarkPerHotelMisses(b *testing.B) {
+	req := benchRequest()
+	srv, evict := benchServer(b, req)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		evict()
+		b.StartTimer()
+		getProfilesPerHotel(srv.MongoClient, srv.MemcClient, req)
+	}
+}
+
+func BenchmarkBatchedMisses(b *testing.B) {
+	req := benchRequest()
+	srv, evict := benchServer(b, req)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		evict()
+		b.StartTimer()
+		if _, err := srv.GetProfiles(context.Background(), req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// getProfilesPerHotel is the miss path GetProfiles had before batching.
+func getProfilesPerHotel(client *mongo.Client, memc *memcache.Client, req *pb.Request) []*pb.Hotel {
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	keys := make([]string, 0)
+	profileMap := make(map[string]struct{})
+	for _, hotelId := range req.HotelIds {
+		keys = append(keys, memcKey(hotelId, req.Locale))
+		profileMap[hotelId] = struct{}{}
+	}
+
+	resMap, _ := memc.GetMulti(keys)
+	hotels := make([]*pb.Hotel, 0)
+	for _, item := range resMap {
+		hotelProf := new(pb.Hotel)
+		json.Unmarshal(item.Value, hotelProf)
+		hotels = append(hotels, hotelProf)
+		delete(profileMap, hotelProf.Id)
+	}
+
+	wg.Add(len(profileMap))
+	for hotelId := range profileMap {
+		go func(hotelId string) {
+			defer wg.Done()
+			var hotelProf *pb.Hotel
+			collection := client.Database("profile-db").Collection("hotels")
+			if err := collection.FindOne(context.TODO(), bson.M{"id": hotelId}).Decode(&hotelProf); err != nil {
+				return
+			}
+
+			mutex.Lock()
+			hotels = append(hotels, hotelProf)
+			mutex.Unlock()
+
+			profJson, _ := json.Marshal(hotelProf)
+			go memc.Set(&memcache.Item{Key: memcKey(hotelId, req.Locale), Value: profJson})
+		}(hotelId)
+	}
+	wg.Wait()
+	return hotels
+}
+
+func TestGetProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		cached   []string // hotels in memcached before the call
+		stored   []string // hotels in mongo
+		rejected []string // hotels memcached refuses to store
+		request  []string
+		want     []string
+		queried  []string // hotels of the $in query, if any
+		written  []string // hotels cached by the call
+	}{
+		{
+			name:    "all cached",
+			cached:  []string{"1", "2"},
+			stored:  []string{"1", "2"},
+			request: []string{"2", "1"},
+			want:    []string{"2", "1"},
+		},
+		{
+			name:    "misses batched",
+			cached:  []string{"2"},
+			stored:  []string{"1", "2", "3"},
+			request: []string{"3", "2", "1", "3"},
+			want:    []string{"3", "2", "1"},
+			queried: []string{"1", "3"},
+			written: []string{"1", "3"},
+		},
+		{
+			name:    "unknown hotels left out",
+			stored:  []string{"1"},
+			request: []string{"1", "4"},
+			want:    []string{"1"},
+			queried: []string{"1", "4"},
+			written: []string{"1"},
+		},
+		{
+			name:     "cache writes failing",
+			stored:   []string{"1", "2", "3"},
+			rejected: []string{"2"},
+			request:  []string{"1", "2", "3"},
+			want:     []string{"1", "2", "3"},
+			queried:  []string{"1", "2", "3"},
+			written:  []string{"1", "3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memc := newFakeMemc(t)
+			for _, id := range tt.cached {
+				memc.items[memcKey(id, "en")] = profileJSON(t, id)
+			}
+			for _, id := range tt.rejected {
+				memc.rejected[memcKey(id, "en")] = true
+			}
+			db := &fakeMongo{hotels: make(map[string]Hotel)}
+			for _, id := range tt.stored {
+				db.hotels[id] = *hotelFromProto(benchHotel(id))
+			}
+			srv := &Server{MongoClient: fakeMongoClient(t, db), MemcClient: memcache.New(memc.addr)}
+
+			res, err := srv.GetProfiles(context.Background(), &pb.Request{HotelIds: tt.request, Locale: "en"})
+			if err != nil {
+				t.Fatalf("GetProfiles: %v", err)
+			}
+			if len(res.Hotels) != len(tt.want) {
+				t.Fatalf("got %d profiles, want %v", len(res.Hotels), tt.want)
+			}
+			for i, id := range tt.want {
+				if want := hotelFromProto(benchHotel(id)).toProto(); !proto.Equal(res.Hotels[i], want) {
+					t.Errorf("profile %d = %v, want %v", i, res.Hotels[i], want)
+				}
+			}
+
+			var queried []string
+			if len(db.queries) > 1 {
+				t.Errorf("queried mongo %d times, want at most once", len(db.queries))
+			} else if len(db.queries) == 1 {
+				queried = db.queries[0]
+			}
+			if !sameIds(queried, tt.queried) {
+				t.Errorf("queried hotels %v, want %v", queried, tt.queried)
+			}
+			if written := memc.written(); !sameIds(written, tt.written) {
+				t.Errorf("cached hotels %v, want %v", written, tt.written)
+			}
+		})
+	}
+}
+
+func TestGetProfilesUnavailable(t *testing.T) {
+	t.Run("memcached down", func(t *testing.T) {
+		db := &fakeMongo{hotels: map[string]Hotel{"1": *hotelFromProto(benchHotel("1"))}}
+		// nothing listens on the port of a closed fake
+		memc := newFakeMemc(t)
+		memc.ln.Close()
+		srv := &Server{MongoClient: fakeMongoClient(t, db), MemcClient: memcache.New(memc.addr)}
+
+		res, err := srv.GetProfiles(context.Background(), &pb.Request{HotelIds: []string{"1"}, Locale: "en"})
+		if err != nil {
+			t.Fatalf("GetProfiles: %v", err)
+		}
+		if len(res.Hotels) != 1 || res.Hotels[0].Id != "1" {
+			t.Errorf("got %v, want the profile of hotel 1 from mongo", res.Hotels)
+		}
+	})
+
+	t.Run("mongo failing", func(t *testing.T) {
+		db := &fakeMongo{fail: true}
+		memc := newFakeMemc(t)
+		memc.items[memcKey("1", "en")] = profileJSON(t, "1")
+		srv := &Server{MongoClient: fakeMongoClient(t, db), MemcClient: memcache.New(memc.addr)}
+
+		_, err := srv.GetProfiles(context.Background(), &pb.Request{HotelIds: []string{"1", "2"}, Locale: "en"})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("GetProfiles error = %v, want Internal", err)
+		}
+		if written := memc.written(); len(written) != 0 {
+			t.Errorf("cached hotels %v after a failed query", written)
+		}
+	})
+}
+
+func profileJSON(t *testing.T, id string) []byte {
+	data, err := json.Marshal(hotelFromProto(benchHotel(id)).toProto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// sameIds reports whether a and b hold the same hotel ids in any order.
+func sameIds(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
+
+// fakeMemc is a memcached server that answers the gets and set commands of
+// the memcache client from a map.
+type fakeMemc struct {
+	ln   net.Listener
+	addr string
+
+	mu       sync.Mutex
+	items    map[string][]byte
+	rejected map[string]bool // keys answered with NOT_STORED
+	sets     []string        // keys stored by set
+}
+
+func newFakeMemc(t *testing.T) *fakeMemc {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	m := &fakeMemc{ln: ln, addr: ln.Addr().String(), items: make(map[string][]byte), rejected: make(map[string]bool)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go m.serve(conn)
+		}
+	}()
+	return m
+}
+
+func (m *fakeMemc) serve(conn net.Conn) {
+	defer conn.Close()
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	for {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return
+		}
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			return
+		}
+		m.mu.Lock()
+		switch args[0] {
+		case "gets":
+			for _, key := range args[1:] {
+				if value, ok := m.items[key]; ok {
+					fmt.Fprintf(rw, "VALUE %s 0 %d 1\r\n%s\r\n", key, len(value), value)
+				}
+			}
+			rw.WriteString("END\r\n")
+		case "set":
+			size, _ := strconv.Atoi(args[4])
+			value := make([]byte, size+2)
+			if _, err := io.ReadFull(rw, value); err != nil {
+				m.mu.Unlock()
+				return
+			}
+			if m.rejected[args[1]] {
+				rw.WriteString("NOT_STORED\r\n")
+				break
+			}
+			m.items[args[1]] = value[:size]
+			m.sets = append(m.sets, args[1])
+			rw.WriteString("STORED\r\n")
+		default:
+			rw.WriteString("ERROR\r\n")
+		}
+		m.mu.Unlock()
+		if rw.Flush() != nil {
+			return
+		}
+	}
+}
+
+// written returns the hotels stored by set.
+func (m *fakeMemc) written() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hotelIds := make([]string, len(m.sets))
+	for i, key := range m.sets {
+		hotelIds[i] = strings.TrimSuffix(key, "_en")
+	}
+	return hotelIds
+}
+
+// fakeMongoClient returns a client whose commands are answered by db.
+func fakeMongoClient(t *testing.T, db *fakeMongo) *mongo.Client {
+	opts := options.Client()
+	opts.Deployment = db
+	client, err := mongo.Connect(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+	return client
+}
+
+// fakeMongo is a single server deployment answering find commands on
+// profile-db.hotels by hotel id. It records the ids of each query.
+type fakeMongo struct {
+	hotels map[string]Hotel
+	fail   bool
+
+	mu      sync.Mutex
+	queries [][]string
+	reply   []byte
+}
+
+func (db *fakeMongo) SelectServer(context.Context, description.ServerSelector) (driver.Server, error) {
+	return db, nil
+}
+
+func (db *fakeMongo) Kind() description.TopologyKind { return description.Single }
+
+func (db *fakeMongo) Connection(context.Context) (driver.Connection, error) { return db, nil }
+
+func (db *fakeMongo) RTTMonitor() driver.RTTMonitor { return zeroRTT{} }
+
+func (db *fakeMongo) WriteWireMessage(_ context.Context, wm []byte) error {
+	_, requestID, _, _, rem, ok := wiremessage.ReadHeader(wm)
+	if ok {
+		_, rem, ok = wiremessage.ReadMsgFlags(rem)
+	}
+	if ok {
+		_, rem, ok = wiremessage.ReadMsgSectionType(rem)
+	}
+	var cmd bsoncore.Document
+	if ok {
+		cmd, _, ok = wiremessage.ReadMsgSectionSingleDocument(rem)
+	}
+	if !ok {
+		return fmt.Errorf("malformed wire message")
+	}
+
+	doc, err := db.run(bson.Raw(cmd))
+	if err != nil {
+		return err
+	}
+	idx, reply := wiremessage.AppendHeaderStart(nil, wiremessage.NextRequestID(), requestID, wiremessage.OpMsg)
+	reply = wiremessage.AppendMsgFlags(reply, 0)
+	reply = wiremessage.AppendMsgSectionType(reply, wiremessage.SingleDocument)
+	reply = append(reply, doc...)
+	reply = bsoncore.UpdateLength(reply, idx, int32(len(reply[idx:])))
+
+	db.mu.Lock()
+	db.reply = reply
+	db.mu.Unlock()
+	return nil
+}
+
+// run returns the reply to a command.
+func (db *fakeMongo) run(cmd bson.Raw) (bson.Raw, error) {
+	if _, err := cmd.LookupErr("find"); err != nil {
+		return bson.Marshal(bson.M{"ok": 0, "errmsg": "unsupported command", "code": 59})
+	}
+	if db.fail {
+		return bson.Marshal(bson.M{"ok": 0, "errmsg": "node is recovering", "code": 91})
+	}
+
+	var find struct {
+		Filter struct {
+			Id struct {
+				In []string `bson:"$in"`
+			} `bson:"id"`
+		} `bson:"filter"`
+	}
+	if err := bson.Unmarshal(cmd, &find); err != nil {
+		return nil, err
+	}
+	hotelIds := find.Filter.Id.In
+	batch := bson.A{}
+	for _, id := range hotelIds {
+		if hotel, ok := db.hotels[id]; ok {
+			batch = append(batch, hotel)
+		}
+	}
+	db.mu.Lock()
+	db.queries = append(db.queries, hotelIds)
+	db.mu.Unlock()
+	return bson.Marshal(bson.M{
+		"ok":     1,
+		"cursor": bson.M{"id": int64(0), "ns": "profile-db.hotels", "firstBatch": batch},
+	})
+}
+
+func (db *fakeMongo) ReadWireMessage(context.Context) ([]byte, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.reply, nil
+}
+
+func (db *fakeMongo) Description() description.Server {
+	return description.Server{
+		Addr:        address.Address("fake"),
+		Kind:        description.Standalone,
+		WireVersion: &description.VersionRange{Min: 6, Max: 21},
+	}
+}
+
+func (db *fakeMongo) Close() error               { return nil }
+func (db *fakeMongo) ID() string                 { return "fake" }
+func (db *fakeMongo) ServerConnectionID() *int64 { return nil }
+func (db *fakeMongo) DriverConnectionID() uint64 { return 0 }
+func (db *fakeMongo) Address() address.Address   { return address.Address("fake") }
+func (db *fakeMongo) Stale() bool                { return false }
+func (db *fakeMongo) OIDCTokenGenID() uint64     { return 0 }
+func (db *fakeMongo) SetOIDCTokenGenID(uint64)   {}
+
+type zeroRTT struct{}
+
+func (zeroRTT) EWMA() time.Duration { return 0 }
+func (zeroRTT) Min() time.Duration  { return 0 }
+func (zeroRTT) P90() time.Duration  { return 0 }
+func (zeroRTT) Stats() string       { return "" }
@@ -0,0 +1,227 @@
+package profile
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	pb "hotelReservation/services/profile/proto"
+)
+
+// CreateProfile stores a new hotel profile at version 1.
+func (s *Server) CreateProfile(ctx context.Context, req *pb.Hotel) (*pb.Hotel, error) {
+	log.Trace().Msgf("In CreateProfile")
+
+	hotel := hotelFromProto(req)
+	hotel.Version = 1
+	if err := hotel.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	collection := s.MongoClient.Database("profile-db").Collection("hotels")
+	_, err := collection.InsertOne(ctx, hotel)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "hotel %v already exists", hotel.Id)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to insert hotel [id: %v]: %v", hotel.Id, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// a cached miss of an earlier request may still be around
+	s.invalidate(hotel.Id)
+	return hotel.toProto(), nil
+}
+
+// UpdateProfile changes the fields in the update mask of a hotel profile,
+// provided nobody else changed it since the version in the request.
+func (s *Server) UpdateProfile(ctx context.Context, req *pb.UpdateRequest) (*pb.Hotel, error) {
+	log.Trace().Msgf("In UpdateProfile")
+
+	if req.Hotel == nil || req.Hotel.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "hotel id must be set")
+	}
+	src := hotelFromProto(req.Hotel)
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = hotelFields
+	}
+
+	collection := s.MongoClient.Database("profile-db").Collection("hotels")
+	var hotel Hotel
+	err := collection.FindOne(ctx, bson.M{"id": src.Id}).Decode(&hotel)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "hotel %v not found", src.Id)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed get hotel [id: %v]: %v", src.Id, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if hotel.Version != src.Version {
+		return nil, status.Errorf(codes.Aborted, "hotel %v is at version %d, not %d", src.Id, hotel.Version, src.Version)
+	}
+
+	for _, path := range paths {
+		if err := hotel.merge(src, path); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if err := hotel.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	update, err := updateDocument(&hotel, paths)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res, err := collection.UpdateOne(ctx, bson.M{"id": hotel.Id, "version": hotel.Version}, update)
+	if err != nil {
+		log.Error().Msgf("Failed to update hotel [id: %v]: %v", hotel.Id, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if res.MatchedCount == 0 {
+		return nil, status.Errorf(codes.Aborted, "hotel %v was changed concurrently", hotel.Id)
+	}
+
+	s.invalidate(hotel.Id)
+	hotel.Version++
+	return hotel.toProto(), nil
+}
+
+// updateDocument returns the update writing the top-level fields touched by
+// the mask paths from the merged hotel, and moving it to the next version.
+// The version filter of UpdateProfile makes this safe for nested paths too.
+// Fields the document leaves out when empty are removed.
+func updateDocument(hotel *Hotel, paths []string) (bson.M, error) {
+	doc, err := bson.Marshal(hotel)
+	if err != nil {
+		return nil, err
+	}
+
+	set, unset := bson.M{}, bson.M{}
+	for _, path := range paths {
+		field := strings.SplitN(path, ".", 2)[0]
+		if value, err := bson.Raw(doc).LookupErr(field); err == nil {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
+	}
+
+	update := bson.M{"$inc": bson.M{"version": 1}}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update, nil
+}
+
+// DeleteProfile removes a hotel profile and its translations.
+func (s *Server) DeleteProfile(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResult, error) {
+	log.Trace().Msgf("In DeleteProfile")
+
+	if req.HotelId == "" {
+		return nil, status.Error(codes.InvalidArgument, "hotel id must be set")
+	}
+	filter := bson.M{"id": req.HotelId}
+	if req.Version != 0 {
+		filter["version"] = req.Version
+	}
+
+	db := s.MongoClient.Database("profile-db")
+	res, err := db.Collection("hotels").DeleteOne(ctx, filter)
+	if err != nil {
+		log.Error().Msgf("Failed to delete hotel [id: %v]: %v", req.HotelId, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if res.DeletedCount == 0 && req.Version != 0 {
+		n, err := db.Collection("hotels").CountDocuments(ctx, bson.M{"id": req.HotelId})
+		if err == nil && n > 0 {
+			return nil, status.Errorf(codes.Aborted, "hotel %v is not at version %d", req.HotelId, req.Version)
+		}
+	}
+	if _, err := db.Collection("locales").DeleteMany(ctx, bson.M{"hotelId": req.HotelId}); err != nil {
+		log.Error().Msgf("Failed to delete locales of hotel [id: %v]: %v", req.HotelId, err)
+	}
+
+	s.invalidate(req.HotelId)
+	return &pb.DeleteResult{Existed: res.DeletedCount > 0}, nil
+}
+
+// maxImages is the most images a hotel profile can have.
+const maxImages = 50
+
+// AddImages appends uploaded images to a hotel profile and moves it to the
+// next version.
+func (s *Server) AddImages(ctx context.Context, req *pb.AddImagesRequest) (*pb.Hotel, error) {
+	log.Trace().Msgf("In AddImages")
+
+	if req.HotelId == "" || len(req.Images) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "hotel id and images must be set")
+	}
+	if len(req.Images) > maxImages {
+		return nil, status.Errorf(codes.InvalidArgument, "a hotel has at most %d images", maxImages)
+	}
+	uploadedBy := auth.FromContext(ctx).Username
+	images := make([]*Image, 0, len(req.Images))
+	for _, p := range req.Images {
+		if p.GetId() == "" || p.GetUrl() == "" {
+			return nil, status.Error(codes.InvalidArgument, "images need an id and a url")
+		}
+		img := imageFromProto(p)
+		img.HotelId = req.HotelId
+		img.UploadedBy = uploadedBy
+		images = append(images, img)
+	}
+
+	// the filter fails once the images would go over the limit
+	collection := s.MongoClient.Database("profile-db").Collection("hotels")
+	filter := bson.M{
+		"id": req.HotelId,
+		"images." + strconv.Itoa(maxImages-len(images)): bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$push": bson.M{"images": bson.M{"$each": images}},
+		"$inc":  bson.M{"version": 1},
+	}
+	var hotel Hotel
+	err := collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&hotel)
+	if err == mongo.ErrNoDocuments {
+		n, err := collection.CountDocuments(ctx, bson.M{"id": req.HotelId})
+		if err == nil && n > 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "a hotel has at most %d images", maxImages)
+		}
+		return nil, status.Errorf(codes.NotFound, "hotel %v not found", req.HotelId)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to add images to hotel [id: %v]: %v", req.HotelId, err)
+		return nil, mongoError(err)
+	}
+
+	s.invalidate(hotel.Id)
+	return hotel.toProto(), nil
+}
+
+// invalidate drops the cached profile of a hotel in every locale and mask
+// it can be cached under.
+func (s *Server) invalidate(hotelId string) {
+	masks := append(s.cachedMasks(), "")
+	for locale := range s.locales {
+		for _, mask := range masks {
+			err := s.MemcClient.Delete(profileKey(hotelId, locale, mask))
+			if err != nil && err != memcache.ErrCacheMiss {
+				log.Error().Msgf("Failed to invalidate hotel [id: %v, locale: %v]: %v", hotelId, locale, err)
+			}
+		}
+	}
+}
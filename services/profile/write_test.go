@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func seededHotel() *Hotel {
+	return &Hotel{
+		Id:          "1",
+		Name:        "Clift Hotel",
+		PhoneNumber: "(415) 775-4700",
+		Address:     &Address{"495", "Geary St", "San Francisco", "CA", "United States", "94102", 37.7867, -122.4112},
+		Version:     1,
+	}
+}
+
+// updateFields returns the fields an update sets and removes.
+func updateFields(t *testing.T, update bson.M) (set, unset bson.M) {
+	t.Helper()
+	// the driver marshals the update before sending it
+	if _, err := bson.Marshal(update); err != nil {
+		t.Fatalf("update does not marshal: %v", err)
+	}
+	set, _ = update["$set"].(bson.M)
+	unset, _ = update["$unset"].(bson.M)
+	return set, unset
+}
+
+func TestUpdateDocumentEmptyFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		paths     []string
+		wantSet   []string
+		wantUnset []string
+	}{
+		{"empty images and amenities", []string{"images", "amenities"}, nil, []string{"images", "amenities"}},
+		{"whole profile without images", hotelFields,
+			[]string{"name", "phoneNumber", "description", "address"},
+			[]string{"images", "amenities", "starRating", "checkInTime", "checkOutTime", "roomTypes"}},
+		{"nested path", []string{"address.city", "name"}, []string{"address", "name"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update, err := updateDocument(seededHotel(), tt.paths)
+			if err != nil {
+				t.Fatal(err)
+			}
+			set, unset := updateFields(t, update)
+			if len(set) != len(tt.wantSet) || len(unset) != len(tt.wantUnset) {
+				t.Errorf("update sets %v and removes %v, want %v and %v", set, unset, tt.wantSet, tt.wantUnset)
+			}
+			for _, f := range tt.wantSet {
+				if _, ok := set[f]; !ok {
+					t.Errorf("update does not set %s", f)
+				}
+			}
+			for _, f := range tt.wantUnset {
+				if _, ok := unset[f]; !ok {
+					t.Errorf("update does not remove %s", f)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateDocumentSetFields(t *testing.T) {
+	hotel := seededHotel()
+	hotel.Images = []*Image{{Url: "/images/hotels/1/a.jpg", Default: true}}
+	hotel.Amenities = []string{"wifi"}
+
+	update, err := updateDocument(hotel, []string{"images", "amenities"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, unset := updateFields(t, update)
+	if len(set) != 2 || len(unset) != 0 {
+		t.Errorf("update sets %v and removes %v, want images and amenities set", set, unset)
+	}
+	if inc, _ := update["$inc"].(bson.M); inc["version"] != 1 {
+		t.Errorf("update increments %v, want version by 1", update["$inc"])
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
+	"hotelReservation/auth"
 	"hotelReservation/registry"
 	pb "hotelReservation/services/rate/proto"
 	"hotelReservation/tls"
@@ -40,6 +41,9 @@ type Server struct {
 	MongoClient    *mongo.Client
 	Registry       *registry.Client
 	MemcClient     *memcache.Client
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -59,6 +63,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			otelgrpc.StreamServerInterceptor(
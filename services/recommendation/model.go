@@ -0,0 +1,182 @@
+package recommendation
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	pb "hotelReservation/services/recommendation/proto"
+)
+
+// maxNeighbors is the number of most similar hotels the model keeps for
+// each hotel.
+const maxNeighbors = 20
+
+// Model is an item-item collaborative filtering model built from the
+// reservation history. It is trained offline by cmd/recommendationtrain and
+// loaded by the service at startup.
+type Model struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// Neighbors holds, for each hotel, the hotels booked by the same
+	// customers, most similar first.
+	Neighbors map[string][]Neighbor `json:"neighbors"`
+	// History holds the hotels each customer has booked.
+	History map[string][]string `json:"history"`
+}
+
+type Neighbor struct {
+	HotelId    string  `json:"hotelId"`
+	Similarity float64 `json:"similarity"`
+}
+
+// Booking is a reservation as stored in reservation-db.reservation.
+type Booking struct {
+	HotelId      string `bson:"hotelId"`
+	CustomerName string `bson:"customerName"`
+}
+
+// Train builds a model from bookings. Hotels are compared by the cosine
+// similarity of the sets of customers that booked them.
+func Train(bookings []Booking) *Model {
+	customers := make(map[string]map[string]bool) // hotel -> customers
+	history := make(map[string]map[string]bool)   // customer -> hotels
+	for _, b := range bookings {
+		if b.HotelId == "" || b.CustomerName == "" {
+			continue
+		}
+		if customers[b.HotelId] == nil {
+			customers[b.HotelId] = make(map[string]bool)
+		}
+		if history[b.CustomerName] == nil {
+			history[b.CustomerName] = make(map[string]bool)
+		}
+		customers[b.HotelId][b.CustomerName] = true
+		history[b.CustomerName][b.HotelId] = true
+	}
+
+	// count the customers each pair of hotels has in common
+	common := make(map[string]map[string]int)
+	for _, hotels := range history {
+		for i := range hotels {
+			for j := range hotels {
+				if i == j {
+					continue
+				}
+				if common[i] == nil {
+					common[i] = make(map[string]int)
+				}
+				common[i][j]++
+			}
+		}
+	}
+
+	m := &Model{
+		CreatedAt: time.Now(),
+		Neighbors: make(map[string][]Neighbor, len(common)),
+		History:   make(map[string][]string, len(history)),
+	}
+	for i, counts := range common {
+		neighbors := make([]Neighbor, 0, len(counts))
+		for j, n := range counts {
+			sim := float64(n) / math.Sqrt(float64(len(customers[i])*len(customers[j])))
+			neighbors = append(neighbors, Neighbor{j, sim})
+		}
+		sort.Slice(neighbors, func(a, b int) bool {
+			if neighbors[a].Similarity != neighbors[b].Similarity {
+				return neighbors[a].Similarity > neighbors[b].Similarity
+			}
+			return neighbors[a].HotelId < neighbors[b].HotelId
+		})
+		if len(neighbors) > maxNeighbors {
+			neighbors = neighbors[:maxNeighbors]
+		}
+		m.Neighbors[i] = neighbors
+	}
+	for customer, hotels := range history {
+		for hotelId := range hotels {
+			m.History[customer] = append(m.History[customer], hotelId)
+		}
+		sort.Strings(m.History[customer])
+	}
+	return m
+}
+
+// Recommend returns up to k hotels the customer has not booked, scored by
+// their summed similarity to the hotels the customer has booked and
+// normalized so the best scores 1. It returns nil for customers without
+// history, or whose hotels have no neighbors.
+func (m *Model) Recommend(customer string, k int) []*pb.ScoredHotel {
+	if m == nil || len(m.History[customer]) == 0 {
+		return nil
+	}
+
+	booked := make(map[string]bool)
+	for _, hotelId := range m.History[customer] {
+		booked[hotelId] = true
+	}
+	scores := make(map[string]float64)
+	for hotelId := range booked {
+		for _, n := range m.Neighbors[hotelId] {
+			if !booked[n.HotelId] {
+				scores[n.HotelId] += n.Similarity
+			}
+		}
+	}
+
+	scored := make([]*pb.ScoredHotel, 0, len(scores))
+	best := 0.0
+	for hotelId, score := range scores {
+		scored = append(scored, &pb.ScoredHotel{HotelId: hotelId, Score: score})
+		best = math.Max(best, score)
+	}
+	for _, h := range scored {
+		h.Score /= best
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].HotelId < scored[j].HotelId
+	})
+	if k < len(scored) {
+		scored = scored[:k]
+	}
+	return scored
+}
+
+// LoadModel reads a model written by Save.
+func LoadModel(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := new(Model)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Save writes the model to path, replacing any earlier model atomically.
+func (m *Model) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := json.NewEncoder(tmp).Encode(m); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion7
 
 const (
 	Recommendation_GetRecommendations_FullMethodName = "/recommendation.Recommendation/GetRecommendations"
+	Recommendation_Reload_FullMethodName             = "/recommendation.Recommendation/Reload"
 )
 
 // RecommendationClient is the client API for Recommendation service.
@@ -28,6 +29,9 @@ const (
 type RecommendationClient interface {
 	// GetRecommendations returns recommended hotels for a given requirement
 	GetRecommendations(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// Reload rereads the hotels from recommendation-db and the model file,
+	// and swaps them in for new requests.
+	Reload(ctx context.Context, in *ReloadRequest, opts ...grpc.CallOption) (*ReloadResult, error)
 }
 
 type recommendationClient struct {
@@ -47,12 +51,24 @@ func (c *recommendationClient) GetRecommendations(ctx context.Context, in *Reque
 	return out, nil
 }
 
+func (c *recommendationClient) Reload(ctx context.Context, in *ReloadRequest, opts ...grpc.CallOption) (*ReloadResult, error) {
+	out := new(ReloadResult)
+	err := c.cc.Invoke(ctx, Recommendation_Reload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RecommendationServer is the server API for Recommendation service.
 // All implementations must embed UnimplementedRecommendationServer
 // for forward compatibility
 type RecommendationServer interface {
 	// GetRecommendations returns recommended hotels for a given requirement
 	GetRecommendations(context.Context, *Request) (*Result, error)
+	// Reload rereads the hotels from recommendation-db and the model file,
+	// and swaps them in for new requests.
+	Reload(context.Context, *ReloadRequest) (*ReloadResult, error)
 	mustEmbedUnimplementedRecommendationServer()
 }
 
@@ -63,6 +79,9 @@ type UnimplementedRecommendationServer struct {
 func (UnimplementedRecommendationServer) GetRecommendations(context.Context, *Request) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRecommendations not implemented")
 }
+func (UnimplementedRecommendationServer) Reload(context.Context, *ReloadRequest) (*ReloadResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reload not implemented")
+}
 func (UnimplementedRecommendationServer) mustEmbedUnimplementedRecommendationServer() {}
 
 // UnsafeRecommendationServer may be embedded to opt out of forward compatibility for this service.
@@ -94,6 +113,24 @@ func _Recommendation_GetRecommendations_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Recommendation_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecommendationServer).Reload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Recommendation_Reload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecommendationServer).Reload(ctx, req.(*ReloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Recommendation_ServiceDesc is the grpc.ServiceDesc for Recommendation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -105,6 +142,10 @@ var Recommendation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRecommendations",
 			Handler:    _Recommendation_GetRecommendations_Handler,
 		},
+		{
+			MethodName: "Reload",
+			Handler:    _Recommendation_Reload_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/recommendation/proto/recommendation.proto",
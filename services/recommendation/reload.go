@@ -0,0 +1,125 @@
+package recommendation
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "hotelReservation/services/recommendation/proto"
+)
+
+// dataset is what recommendations are computed from. It is never changed
+// after it is loaded; a reload swaps in a new one.
+type dataset struct {
+	hotels   map[string]Hotel
+	model    *Model
+	loadedAt time.Time
+}
+
+// Reload rereads the dataset and swaps it in.
+func (s *Server) Reload(ctx context.Context, req *pb.ReloadRequest) (*pb.ReloadResult, error) {
+	log.Trace().Msgf("In Reload")
+
+	d, err := s.reload(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "reload failed, still serving the dataset loaded at %v: %v", s.data.Load().loadedAt, err)
+	}
+	customers := 0
+	if d.model != nil {
+		customers = len(d.model.History)
+	}
+	return &pb.ReloadResult{
+		Hotels:    int32(len(d.hotels)),
+		Customers: int32(customers),
+		LoadedAt:  d.loadedAt.Unix(),
+	}, nil
+}
+
+// reload loads a new dataset and makes it current. Requests in flight keep
+// using the dataset they started with. A failed reload leaves the current
+// dataset in place.
+func (s *Server) reload(ctx context.Context) (*dataset, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	hotels, err := loadRecommendations(ctx, s.MongoClient)
+	if err != nil {
+		log.Error().Msgf("Failed to reload recommendation hotels: %v", err)
+		return nil, err
+	}
+
+	d := &dataset{hotels: hotels, loadedAt: time.Now()}
+	if prev := s.data.Load(); prev != nil {
+		d.model = prev.model
+	}
+	if s.ModelPath != "" {
+		model, err := LoadModel(s.ModelPath)
+		if err != nil {
+			log.Warn().Msgf("Failed to load recommendation model %v, keeping the previous one: %v", s.ModelPath, err)
+		} else {
+			d.model = model
+		}
+	}
+
+	s.data.Store(d)
+	log.Info().Msgf("Loaded %d recommendation hotels", len(hotels))
+	return d, nil
+}
+
+// reloadLoop reloads the dataset every ReloadInterval until ctx is done.
+func (s *Server) reloadLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reload(ctx)
+		}
+	}
+}
+
+// registerMetrics reports the size and age of the current dataset to the
+// meter provider of the server.
+func (s *Server) registerMetrics() error {
+	mp := s.MeterProvider
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(name)
+	hotels, err := meter.Int64ObservableGauge("recommendation.dataset.hotels",
+		metric.WithDescription("Number of hotels in the recommendation dataset"))
+	if err != nil {
+		return err
+	}
+	customers, err := meter.Int64ObservableGauge("recommendation.dataset.customers",
+		metric.WithDescription("Number of customers in the collaborative filtering model"))
+	if err != nil {
+		return err
+	}
+	age, err := meter.Float64ObservableGauge("recommendation.dataset.age",
+		metric.WithDescription("Time since the recommendation dataset was loaded"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		d := s.data.Load()
+		if d == nil {
+			return nil
+		}
+		o.ObserveInt64(hotels, int64(len(d.hotels)))
+		if d.model != nil {
+			o.ObserveInt64(customers, int64(len(d.model.History)))
+		}
+		o.ObserveFloat64(age, time.Since(d.loadedAt).Seconds())
+		return nil
+	}, hotels, customers, age)
+	return err
+}
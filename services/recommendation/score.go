@@ -0,0 +1,117 @@
+package recommendation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hailocab/go-geoindex"
+	pb "hotelReservation/services/recommendation/proto"
+)
+
+// defaultTopK is the number of hotels returned when neither the request nor
+// the server sets one.
+const defaultTopK = 5
+
+// criteria are the scoring criteria of a request. Each one maps a hotel to a
+// raw value and tells whether a lower value is better.
+var criteria = map[string]struct {
+	value       func(h Hotel, from geoindex.Point) float64
+	lowerIsBest bool
+}{
+	"dis": {
+		func(h Hotel, from geoindex.Point) float64 {
+			return float64(geoindex.Distance(from, &geoindex.GeoPoint{Plat: h.HLat, Plon: h.HLon})) / 1000
+		},
+		true,
+	},
+	"rate": {
+		func(h Hotel, _ geoindex.Point) float64 { return h.HRate },
+		false,
+	},
+	"price": {
+		func(h Hotel, _ geoindex.Point) float64 { return h.HPrice },
+		true,
+	},
+}
+
+// requestWeights returns the criterion weights of a request, falling back to
+// its single require criterion. It returns nil if the request sets neither.
+func requestWeights(req *pb.Request) (map[string]float64, error) {
+	weights := req.Weights
+	if len(weights) == 0 {
+		if req.Require == "" {
+			return nil, nil
+		}
+		weights = map[string]float64{req.Require: 1}
+	}
+
+	total := 0.0
+	for criterion, w := range weights {
+		if _, ok := criteria[criterion]; !ok {
+			return nil, fmt.Errorf("unknown criterion %q, want dis, rate or price", criterion)
+		}
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("weight of %q must be a non-negative number", criterion)
+		}
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("at least one criterion needs a positive weight")
+	}
+	return weights, nil
+}
+
+// score ranks hotels by the weighted sum of their min-max normalized
+// criteria and returns the best k.
+func score(hotels map[string]Hotel, weights map[string]float64, from geoindex.Point, k int) []*pb.ScoredHotel {
+	scored := make([]*pb.ScoredHotel, 0, len(hotels))
+	if len(hotels) == 0 {
+		return scored
+	}
+
+	ids := make([]string, 0, len(hotels))
+	for id := range hotels {
+		ids = append(ids, id)
+		scored = append(scored, &pb.ScoredHotel{HotelId: id})
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	for criterion, w := range weights {
+		if w == 0 {
+			continue
+		}
+		c := criteria[criterion]
+		values := make([]float64, len(ids))
+		min, max := math.MaxFloat64, -math.MaxFloat64
+		for i, id := range ids {
+			values[i] = c.value(hotels[id], from)
+			min = math.Min(min, values[i])
+			max = math.Max(max, values[i])
+		}
+		for i, v := range values {
+			norm := 1.0
+			if max > min {
+				norm = (v - min) / (max - min)
+				if c.lowerIsBest {
+					norm = 1 - norm
+				}
+			}
+			scored[i].Score += w / total * norm
+		}
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].HotelId < scored[j].HotelId
+	})
+	if k < len(scored) {
+		scored = scored[:k]
+	}
+	return scored
+}
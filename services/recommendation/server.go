@@ -3,8 +3,9 @@ package recommendation
 import (
 	"context"
 	"fmt"
-	"math"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,9 +15,13 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
 	"hotelReservation/registry"
 	pb "hotelReservation/services/recommendation/proto"
 	"hotelReservation/tls"
@@ -28,15 +33,30 @@ const name = "srv-recommendation"
 type Server struct {
 	pb.UnimplementedRecommendationServer
 
-	hotels map[string]Hotel
-	uuid   string
+	data     atomic.Pointer[dataset]
+	reloadMu sync.Mutex
+	uuid     string
 
 	Tracer         trace.Tracer
 	TracerProvider trace.TracerProvider
+	// MeterProvider exports the dataset metrics.
+	MeterProvider metric.MeterProvider
 	Port           int
 	IpAddr         string
 	MongoClient    *mongo.Client
 	Registry       *registry.Client
+	// TopK is the number of hotels returned when a request does not ask
+	// for a number.
+	TopK int
+	// ModelPath is the collaborative filtering model written by
+	// cmd/recommendationtrain. Without it every customer is a cold start.
+	ModelPath string
+	// ReloadInterval is how often the dataset is reloaded. 0 reloads only
+	// on a Reload call.
+	ReloadInterval time.Duration
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -45,8 +65,15 @@ func (s *Server) Run() error {
 		return fmt.Errorf("server port must be set")
 	}
 
-	if s.hotels == nil {
-		s.hotels = loadRecommendations(s.MongoClient)
+	if _, err := s.reload(context.Background()); err != nil {
+		// serve an empty dataset until a reload succeeds
+		s.data.Store(&dataset{hotels: make(map[string]Hotel), loadedAt: time.Now()})
+	}
+	if s.ReloadInterval > 0 {
+		go s.reloadLoop(context.Background())
+	}
+	if err := s.registerMetrics(); err != nil {
+		log.Error().Msgf("Failed to register recommendation metrics: %v", err)
 	}
 
 	s.uuid = uuid.New().String()
@@ -59,6 +86,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			otelgrpc.StreamServerInterceptor(
@@ -111,81 +139,59 @@ func (s *Server) Shutdown() {
 	s.Registry.Deregister(s.uuid)
 }
 
-// GiveRecommendation returns recommendations within a given requirement.
+// GetRecommendations returns the hotels most similar to the ones the
+// customer has booked, or for customers without reservations the hotels
+// that score best on the weighted criteria of the request.
 func (s *Server) GetRecommendations(ctx context.Context, req *pb.Request) (*pb.Result, error) {
 	res := new(pb.Result)
 	log.Trace().Msgf("GetRecommendations")
-	require := req.Require
-	if require == "dis" {
-		p1 := &geoindex.GeoPoint{
-			Pid:  "",
-			Plat: req.Lat,
-			Plon: req.Lon,
-		}
-		min := math.MaxFloat64
-		for _, hotel := range s.hotels {
-			tmp := float64(geoindex.Distance(p1, &geoindex.GeoPoint{
-				Pid:  "",
-				Plat: hotel.HLat,
-				Plon: hotel.HLon,
-			})) / 1000
-			if tmp < min {
-				min = tmp
-			}
-		}
-		for _, hotel := range s.hotels {
-			tmp := float64(geoindex.Distance(p1, &geoindex.GeoPoint{
-				Pid:  "",
-				Plat: hotel.HLat,
-				Plon: hotel.HLon,
-			})) / 1000
-			if tmp == min {
-				res.HotelIds = append(res.HotelIds, hotel.HId)
-			}
-		}
-	} else if require == "rate" {
-		max := 0.0
-		for _, hotel := range s.hotels {
-			if hotel.HRate > max {
-				max = hotel.HRate
-			}
-		}
-		for _, hotel := range s.hotels {
-			if hotel.HRate == max {
-				res.HotelIds = append(res.HotelIds, hotel.HId)
-			}
-		}
-	} else if require == "price" {
-		min := math.MaxFloat64
-		for _, hotel := range s.hotels {
-			if hotel.HPrice < min {
-				min = hotel.HPrice
-			}
-		}
-		for _, hotel := range s.hotels {
-			if hotel.HPrice == min {
-				res.HotelIds = append(res.HotelIds, hotel.HId)
-			}
+
+	weights, err := requestWeights(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	k := int(req.K)
+	if k < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "k must not be negative, got %d", k)
+	}
+	if k == 0 {
+		k = s.TopK
+	}
+	if k <= 0 {
+		k = defaultTopK
+	}
+
+	d := s.data.Load()
+	if req.CustomerName != "" {
+		res.Hotels = d.model.Recommend(req.CustomerName, k)
+		res.Personalized = len(res.Hotels) > 0
+	}
+	if !res.Personalized {
+		// cold start, or no customer given
+		if weights == nil {
+			return nil, status.Error(codes.InvalidArgument, "require or weights must be set")
 		}
-	} else {
-		log.Warn().Msgf("Wrong require parameter: %v", require)
+		from := &geoindex.GeoPoint{Plat: req.Lat, Plon: req.Lon}
+		res.Hotels = score(d.hotels, weights, from, k)
+	}
+	for _, hotel := range res.Hotels {
+		res.HotelIds = append(res.HotelIds, hotel.HotelId)
 	}
 
 	return res, nil
 }
 
 // loadRecommendations loads hotel recommendations from mongodb.
-func loadRecommendations(client *mongo.Client) map[string]Hotel {
+func loadRecommendations(ctx context.Context, client *mongo.Client) (map[string]Hotel, error) {
 	collection := client.Database("recommendation-db").Collection("recommendation")
-	curr, err := collection.Find(context.TODO(), bson.D{})
+	curr, err := collection.Find(ctx, bson.D{})
 	if err != nil {
-		log.Error().Msgf("Failed get hotels data: ", err)
+		return nil, err
 	}
 
 	var hotels []Hotel
-	curr.All(context.TODO(), &hotels)
-	if err != nil {
-		log.Error().Msgf("Failed get hotels data: ", err)
+	if err := curr.All(ctx, &hotels); err != nil {
+		return nil, err
 	}
 
 	profiles := make(map[string]Hotel)
@@ -193,7 +199,7 @@ func loadRecommendations(client *mongo.Client) map[string]Hotel {
 		profiles[hotel.HId] = hotel
 	}
 
-	return profiles
+	return profiles, nil
 }
 
 type Hotel struct {
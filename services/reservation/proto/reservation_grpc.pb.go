@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion7
 const (
 	Reservation_MakeReservation_FullMethodName   = "/reservation.Reservation/MakeReservation"
 	Reservation_CheckAvailability_FullMethodName = "/reservation.Reservation/CheckAvailability"
+	Reservation_CompletedStay_FullMethodName     = "/reservation.Reservation/CompletedStay"
 )
 
 // ReservationClient is the client API for Reservation service.
@@ -31,6 +32,8 @@ type ReservationClient interface {
 	MakeReservation(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
 	// CheckAvailability checks if given information is available
 	CheckAvailability(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// CompletedStay checks if a customer has stayed at a hotel and checked out
+	CompletedStay(ctx context.Context, in *StayRequest, opts ...grpc.CallOption) (*StayResult, error)
 }
 
 type reservationClient struct {
@@ -59,6 +62,15 @@ func (c *reservationClient) CheckAvailability(ctx context.Context, in *Request,
 	return out, nil
 }
 
+func (c *reservationClient) CompletedStay(ctx context.Context, in *StayRequest, opts ...grpc.CallOption) (*StayResult, error) {
+	out := new(StayResult)
+	err := c.cc.Invoke(ctx, Reservation_CompletedStay_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ReservationServer is the server API for Reservation service.
 // All implementations must embed UnimplementedReservationServer
 // for forward compatibility
@@ -67,6 +79,8 @@ type ReservationServer interface {
 	MakeReservation(context.Context, *Request) (*Result, error)
 	// CheckAvailability checks if given information is available
 	CheckAvailability(context.Context, *Request) (*Result, error)
+	// CompletedStay checks if a customer has stayed at a hotel and checked out
+	CompletedStay(context.Context, *StayRequest) (*StayResult, error)
 	mustEmbedUnimplementedReservationServer()
 }
 
@@ -80,6 +94,9 @@ func (UnimplementedReservationServer) MakeReservation(context.Context, *Request)
 func (UnimplementedReservationServer) CheckAvailability(context.Context, *Request) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckAvailability not implemented")
 }
+func (UnimplementedReservationServer) CompletedStay(context.Context, *StayRequest) (*StayResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompletedStay not implemented")
+}
 func (UnimplementedReservationServer) mustEmbedUnimplementedReservationServer() {}
 
 // UnsafeReservationServer may be embedded to opt out of forward compatibility for this service.
@@ -129,6 +146,24 @@ func _Reservation_CheckAvailability_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Reservation_CompletedStay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServer).CompletedStay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Reservation_CompletedStay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServer).CompletedStay(ctx, req.(*StayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Reservation_ServiceDesc is the grpc.ServiceDesc for Reservation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -144,6 +179,10 @@ var Reservation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckAvailability",
 			Handler:    _Reservation_CheckAvailability_Handler,
 		},
+		{
+			MethodName: "CompletedStay",
+			Handler:    _Reservation_CompletedStay_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/reservation/proto/reservation.proto",
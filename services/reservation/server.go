@@ -14,11 +14,15 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
 	"hotelReservation/registry"
 	pb "hotelReservation/services/reservation/proto"
 	"hotelReservation/tls"
@@ -39,6 +43,9 @@ type Server struct {
 	MongoClient    *mongo.Client
 	Registry       *registry.Client
 	MemcClient     *memcache.Client
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -58,6 +65,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			otelgrpc.StreamServerInterceptor(
@@ -115,6 +123,20 @@ func (s *Server) Shutdown() {
 
 // MakeReservation makes a reservation based on given information
 func (s *Server) MakeReservation(ctx context.Context, req *pb.Request) (*pb.Result, error) {
+	// users book for themselves; a guest they book for is only recorded
+	id := auth.FromContext(ctx)
+	if id.Username == "" {
+		return nil, status.Error(codes.Unauthenticated, "login required")
+	}
+	if req.CustomerName != "" && req.CustomerName != id.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "%s cannot book as %s", id.Username, req.CustomerName)
+	}
+	// CompletedStay counts nights whose check-out date has come, so stays
+	// cannot be booked in the past
+	if err := checkDates(req.InDate, req.OutDate, time.Now()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	res := new(pb.Result)
 	res.HotelId = make([]string, 0)
 
@@ -223,7 +245,8 @@ func (s *Server) MakeReservation(ctx context.Context, req *pb.Request) (*pb.Resu
 			context.TODO(),
 			reservation{
 				HotelId:      hotelId,
-				CustomerName: req.CustomerName,
+				CustomerName: id.Username,
+				GuestName:    req.GuestName,
 				InDate:       indate,
 				OutDate:      outdate,
 				Number:       int(req.RoomNumber),
@@ -429,12 +452,72 @@ func (s *Server) CheckAvailability(ctx context.Context, req *pb.Request) (*pb.Re
 	return res, nil
 }
 
+// checkDates checks that a stay from inDate to outDate, YYYY-MM-DD, lasts at
+// least a night and does not start before the UTC day of now.
+func checkDates(inDate, outDate string, now time.Time) error {
+	in, err := time.Parse("2006-01-02", inDate)
+	if err != nil {
+		return fmt.Errorf("invalid inDate %q", inDate)
+	}
+	out, err := time.Parse("2006-01-02", outDate)
+	if err != nil {
+		return fmt.Errorf("invalid outDate %q", outDate)
+	}
+	if !out.After(in) {
+		return fmt.Errorf("outDate %s is not after inDate %s", outDate, inDate)
+	}
+	if inDate < now.UTC().Format("2006-01-02") {
+		return fmt.Errorf("inDate %s is in the past", inDate)
+	}
+	return nil
+}
+
+// CompletedStay checks if a customer has stayed at a hotel and checked out,
+// which lets them review it. Users may only ask about their own stays,
+// besides admins and managers of the hotel.
+func (s *Server) CompletedStay(ctx context.Context, req *pb.StayRequest) (*pb.StayResult, error) {
+	if req.CustomerName == "" || req.HotelId == "" {
+		return nil, status.Error(codes.InvalidArgument, "customerName and hotelId are required")
+	}
+	id := auth.FromContext(ctx)
+	if id.Username != req.CustomerName && id.Role != auth.RoleAdmin && !id.Manages(req.HotelId) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s cannot check the stays of %s", id.Username, req.CustomerName)
+	}
+
+	// reservations are stored per night, so a night is over once its
+	// check-out date has come
+	today := time.Now().UTC().Format("2006-01-02")
+	filter := bson.M{
+		"customerName": req.CustomerName,
+		"hotelId":      req.HotelId,
+		"outDate":      bson.M{"$lte": today},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "outDate", Value: -1}})
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_completed_stay", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	var r reservation
+	err := s.MongoClient.Database("reservation-db").Collection("reservation").FindOne(ctx, filter, opts).Decode(&r)
+	if err == mongo.ErrNoDocuments {
+		return &pb.StayResult{}, nil
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to find stays of customer [%v] at hotel [%v]: %v", req.CustomerName, req.HotelId, err)
+		return nil, status.Error(codes.Internal, "failed to find stays")
+	}
+
+	return &pb.StayResult{Completed: true, OutDate: r.OutDate}, nil
+}
+
 type reservation struct {
 	HotelId      string `bson:"hotelId"`
 	CustomerName string `bson:"customerName"`
-	InDate       string `bson:"inDate"`
-	OutDate      string `bson:"outDate"`
-	Number       int    `bson:"number"`
+	// GuestName is who stays, when not the customer.
+	GuestName string `bson:"guestName,omitempty"`
+	InDate    string `bson:"inDate"`
+	OutDate   string `bson:"outDate"`
+	Number    int    `bson:"number"`
 }
 
 type number struct {
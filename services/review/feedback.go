@@ -0,0 +1,190 @@
+package review
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	pb "hotelReservation/services/review/proto"
+)
+
+const maxReplyLength = 2000
+
+// ReplyHelper is the reply of a hotel to a review.
+type ReplyHelper struct {
+	Text      string    `bson:"text"`
+	Author    string    `bson:"author"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+// vote is the helpful vote of a user, kept in review-db.votes with a
+// unique index on the review and the user.
+type vote struct {
+	ReviewId  string    `bson:"reviewId"`
+	Username  string    `bson:"username"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+func (r *ReplyHelper) proto() *pb.Reply {
+	if r == nil {
+		return nil
+	}
+	return &pb.Reply{Text: r.Text, Author: r.Author, CreatedAt: r.CreatedAt.Unix()}
+}
+
+// findPublished returns a review users can see.
+func (s *Server) findPublished(ctx context.Context, reviewId string) (ReviewHelper, error) {
+	var r ReviewHelper
+	err := s.MongoClient.Database("review-db").Collection("reviews").FindOne(ctx, bson.M{"reviewId": reviewId}).Decode(&r)
+	if err == mongo.ErrNoDocuments || (err == nil && !published(r.Status)) {
+		return r, status.Errorf(codes.NotFound, "review %s not found", reviewId)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to get review [%v]: %v", reviewId, err)
+		return r, status.Error(codes.Internal, "failed to get review")
+	}
+	return r, nil
+}
+
+// VoteHelpful records or withdraws the helpful vote of the calling user on
+// a review and counts it in the review.
+func (s *Server) VoteHelpful(ctx context.Context, req *pb.VoteRequest) (*pb.ReviewComm, error) {
+	id := auth.FromContext(ctx)
+	if id.Username == "" {
+		return nil, status.Error(codes.Unauthenticated, "login required")
+	}
+	if req.ReviewId == "" {
+		return nil, status.Error(codes.InvalidArgument, "reviewId is required")
+	}
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_vote_review", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	r, err := s.findPublished(ctx, req.ReviewId)
+	if err != nil {
+		return nil, err
+	}
+	if r.Username == id.Username {
+		return nil, status.Error(codes.PermissionDenied, "authors cannot vote on their own reviews")
+	}
+
+	votes := s.MongoClient.Database("review-db").Collection("votes")
+	if req.Helpful {
+		_, err = votes.InsertOne(ctx, vote{
+			ReviewId:  r.ReviewId,
+			Username:  id.Username,
+			CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			err = nil
+		}
+	} else {
+		_, err = votes.DeleteOne(ctx, bson.M{"reviewId": r.ReviewId, "username": id.Username})
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to record vote of user [%v] on review [%v]: %v", id.Username, r.ReviewId, err)
+		return nil, status.Error(codes.Internal, "failed to record vote")
+	}
+
+	// counting rather than incrementing the votes also repairs the count
+	// of a vote that failed to count it, with the next vote on the review
+	prev := r.HelpfulCount
+	r, err = s.countVotes(ctx, r.ReviewId)
+	if err != nil {
+		log.Error().Msgf("Failed to count vote of user [%v] on review [%v]: %v", id.Username, req.ReviewId, err)
+		return nil, status.Error(codes.Internal, "failed to count vote")
+	}
+
+	if r.HelpfulCount != prev {
+		s.invalidateReviews(ctx, r.HotelId)
+	}
+	return publishedComm(r), nil
+}
+
+// maxRecounts bounds the counts of countVotes under concurrent votes. A
+// count it leaves behind is corrected by the next vote on the review.
+const maxRecounts = 5
+
+// countVotes sets the helpfulCount of a review to its number of votes and
+// returns the review. The votes are counted again after the count is set,
+// and it is set again until they agree, so that the count set last by
+// concurrent votes includes all of them.
+func (s *Server) countVotes(ctx context.Context, reviewId string) (ReviewHelper, error) {
+	db := s.MongoClient.Database("review-db")
+	filter := bson.M{"reviewId": reviewId}
+
+	var r ReviewHelper
+	count, err := db.Collection("votes").CountDocuments(ctx, filter)
+	for i := 0; err == nil; i++ {
+		err = db.Collection("reviews").FindOneAndUpdate(ctx, filter,
+			bson.M{"$set": bson.M{"helpfulCount": count}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&r)
+		if err != nil {
+			break
+		}
+		var recount int64
+		recount, err = db.Collection("votes").CountDocuments(ctx, filter)
+		if err != nil || recount == count || i == maxRecounts {
+			break
+		}
+		count = recount
+	}
+	return r, err
+}
+
+// ReplyToReview posts the reply of a manager of the hotel, or an admin, to a
+// review. Replies cannot be changed once posted.
+func (s *Server) ReplyToReview(ctx context.Context, req *pb.ReplyRequest) (*pb.ReviewComm, error) {
+	id := auth.FromContext(ctx)
+	if id.Username == "" {
+		return nil, status.Error(codes.Unauthenticated, "login required")
+	}
+	text := strings.TrimSpace(req.Text)
+	switch {
+	case req.ReviewId == "":
+		return nil, status.Error(codes.InvalidArgument, "reviewId is required")
+	case text == "":
+		return nil, status.Error(codes.InvalidArgument, "text is required")
+	case utf8.RuneCountInString(text) > maxReplyLength:
+		return nil, status.Errorf(codes.InvalidArgument, "text is longer than %d characters", maxReplyLength)
+	}
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_reply_review", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	r, err := s.findPublished(ctx, req.ReviewId)
+	if err != nil {
+		return nil, err
+	}
+	if !id.Manages(r.HotelId) && id.Role != auth.RoleAdmin {
+		return nil, status.Errorf(codes.PermissionDenied, "only managers of hotel %s may reply to its reviews", r.HotelId)
+	}
+
+	reply := &ReplyHelper{Text: text, Author: id.Username, CreatedAt: time.Now().UTC().Truncate(time.Millisecond)}
+	err = s.MongoClient.Database("review-db").Collection("reviews").FindOneAndUpdate(ctx,
+		bson.M{"reviewId": r.ReviewId, "reply": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"reply": reply}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&r)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.AlreadyExists, "review %s already has a reply", r.ReviewId)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to reply to review [%v]: %v", r.ReviewId, err)
+		return nil, status.Error(codes.Internal, "failed to save reply")
+	}
+	log.Info().Msgf("User [%v] replied to review [%v] of hotel [%v]", id.Username, r.ReviewId, r.HotelId)
+
+	s.invalidateReviews(ctx, r.HotelId)
+	return publishedComm(r), nil
+}
@@ -0,0 +1,117 @@
+package review
+
+import (
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	pb "hotelReservation/services/review/proto"
+)
+
+const maxImagesPerReview = 10
+
+func (img ImageHelper) proto() *pb.Image {
+	return &pb.Image{
+		Url:          img.Url,
+		Default:      img.Default,
+		Id:           img.Id,
+		ThumbnailUrl: img.ThumbnailUrl,
+		ContentType:  img.ContentType,
+		Width:        img.Width,
+		Height:       img.Height,
+		Size:         img.Size,
+		ReviewId:     img.ReviewId,
+		HotelId:      img.HotelId,
+		UploadedBy:   img.UploadedBy,
+		Pending:      img.Pending,
+	}
+}
+
+// AddImages attaches uploaded images to a review. Only its author, or an
+// admin, may add them, up to maxImagesPerReview in all. The images wait for
+// moderation like new reviews, and are published once a moderator approves
+// the review, again if it was published already. With validateOnly
+// it only checks that, so that the frontend can do so before storing the
+// uploaded images.
+func (s *Server) AddImages(ctx context.Context, req *pb.AddImagesRequest) (*pb.ReviewComm, error) {
+	id := auth.FromContext(ctx)
+	if id.Username == "" {
+		return nil, status.Error(codes.Unauthenticated, "login required")
+	}
+	if req.ReviewId == "" || len(req.Images) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "reviewId and images are required")
+	}
+	if len(req.Images) > maxImagesPerReview {
+		return nil, status.Errorf(codes.InvalidArgument, "a review has at most %d images", maxImagesPerReview)
+	}
+	for _, img := range req.Images {
+		if !req.ValidateOnly && (img.GetId() == "" || img.GetUrl() == "") {
+			return nil, status.Error(codes.InvalidArgument, "images need an id and a url")
+		}
+	}
+
+	c := s.MongoClient.Database("review-db").Collection("reviews")
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_add_review_images", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	var r ReviewHelper
+	err := c.FindOne(ctx, bson.M{"reviewId": req.ReviewId}).Decode(&r)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "review %s not found", req.ReviewId)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to get review [%v]: %v", req.ReviewId, err)
+		return nil, status.Error(codes.Internal, "failed to get review")
+	}
+	if r.Username != id.Username && id.Role != auth.RoleAdmin {
+		return nil, status.Error(codes.PermissionDenied, "only the author of a review may add images to it")
+	}
+	if req.ValidateOnly {
+		if len(r.Images)+len(req.Images) > maxImagesPerReview {
+			return nil, status.Errorf(codes.InvalidArgument, "a review has at most %d images", maxImagesPerReview)
+		}
+		return reviewComm(r), nil
+	}
+
+	images := make([]ImageHelper, 0, len(req.Images))
+	for _, img := range req.Images {
+		images = append(images, ImageHelper{
+			Url:          img.Url,
+			Id:           img.Id,
+			ThumbnailUrl: img.ThumbnailUrl,
+			ContentType:  img.ContentType,
+			Width:        img.Width,
+			Height:       img.Height,
+			Size:         img.Size,
+			ReviewId:     r.ReviewId,
+			HotelId:      r.HotelId,
+			UploadedBy:   id.Username,
+			Pending:      true,
+		})
+	}
+
+	// the filter fails once concurrent uploads would go over the limit
+	filter := bson.M{
+		"reviewId": req.ReviewId,
+		"images." + strconv.Itoa(maxImagesPerReview-len(images)): bson.M{"$exists": false},
+	}
+	update := bson.M{"$push": bson.M{"images": bson.M{"$each": images}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&r)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.InvalidArgument, "a review has at most %d images", maxImagesPerReview)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to add images to review [%v]: %v", req.ReviewId, err)
+		return nil, status.Error(codes.Internal, "failed to add images")
+	}
+	return reviewComm(r), nil
+}
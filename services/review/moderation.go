@@ -0,0 +1,275 @@
+package review
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	reservation "hotelReservation/services/reservation/proto"
+	pb "hotelReservation/services/review/proto"
+)
+
+// The moderation statuses of reviews. Reviews stored without a status
+// predate moderation and count as approved.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+const (
+	minRating            = 1
+	maxRating            = 5
+	maxDescriptionLength = 5000
+
+	defaultQueueLimit = 50
+	maxQueueLimit     = 200
+)
+
+// publishedFilter matches the reviews of a hotel shown to users.
+func publishedFilter(hotelId string) bson.M {
+	return bson.M{
+		"hotelId": hotelId,
+		"status":  bson.M{"$in": bson.A{StatusApproved, nil}},
+	}
+}
+
+func published(status string) bool {
+	return status == "" || status == StatusApproved
+}
+
+func reviewComm(r ReviewHelper) *pb.ReviewComm {
+	c := &pb.ReviewComm{
+		ReviewId:       r.ReviewId,
+		HotelId:        r.HotelId,
+		Name:           r.Name,
+		Rating:         r.Rating,
+		Description:    r.Description,
+		Status:         r.Status,
+		ModerationNote: r.ModerationNote,
+		HelpfulCount:   r.HelpfulCount,
+		Reply:          r.Reply.proto(),
+	}
+	if c.Status == "" {
+		c.Status = StatusApproved
+	}
+	if !r.CreatedAt.IsZero() {
+		c.CreatedAt = r.CreatedAt.Unix()
+	}
+	for _, img := range r.Images {
+		c.Images = append(c.Images, img.proto())
+	}
+	return c
+}
+
+// publishedComm returns a published review as users see it, without the
+// images waiting for moderation.
+func publishedComm(r ReviewHelper) *pb.ReviewComm {
+	c := reviewComm(r)
+	images := c.Images[:0]
+	for _, img := range c.Images {
+		if !img.Pending {
+			images = append(images, img)
+		}
+	}
+	c.Images = images
+	return c
+}
+
+// SubmitReview adds a review of a hotel by the calling user. Only users who
+// have completed a stay at the hotel may review it, once; the review waits
+// for moderation before it is published.
+func (s *Server) SubmitReview(ctx context.Context, req *pb.SubmitRequest) (*pb.ReviewComm, error) {
+	id := auth.FromContext(ctx)
+	if id.Username == "" {
+		return nil, status.Error(codes.Unauthenticated, "login required")
+	}
+
+	description := strings.TrimSpace(req.Description)
+	switch {
+	case req.HotelId == "":
+		return nil, status.Error(codes.InvalidArgument, "hotelId is required")
+	case math.IsNaN(float64(req.Rating)) || math.IsInf(float64(req.Rating), 0),
+		req.Rating < minRating || req.Rating > maxRating:
+		return nil, status.Errorf(codes.InvalidArgument, "rating must be from %d to %d", minRating, maxRating)
+	case description == "":
+		return nil, status.Error(codes.InvalidArgument, "description is required")
+	case utf8.RuneCountInString(description) > maxDescriptionLength:
+		return nil, status.Errorf(codes.InvalidArgument, "description is longer than %d characters", maxDescriptionLength)
+	}
+
+	stay, err := s.reservationClient.CompletedStay(ctx, &reservation.StayRequest{
+		CustomerName: id.Username,
+		HotelId:      req.HotelId,
+	})
+	if err != nil {
+		log.Error().Msgf("Failed to check stays of user [%v] at hotel [%v]: %v", id.Username, req.HotelId, err)
+		return nil, status.Error(codes.Unavailable, "failed to check stays")
+	}
+	if !stay.Completed {
+		return nil, status.Errorf(codes.PermissionDenied, "only guests who stayed at hotel %s may review it", req.HotelId)
+	}
+
+	r := ReviewHelper{
+		ReviewId:    uuid.New().String(),
+		HotelId:     req.HotelId,
+		Name:        id.Username,
+		Rating:      req.Rating,
+		Description: description,
+		Username:    id.Username,
+		Status:      StatusPending,
+		CreatedAt:   time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_submit_review", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	c := s.MongoClient.Database("review-db").Collection("reviews")
+	if _, err := c.InsertOne(ctx, r); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "user %s already reviewed hotel %s", id.Username, req.HotelId)
+		}
+		log.Error().Msgf("Failed to insert review of hotel [%v]: %v", req.HotelId, err)
+		return nil, status.Error(codes.Internal, "failed to save review")
+	}
+	log.Info().Msgf("User [%v] submitted review [%v] of hotel [%v]", id.Username, r.ReviewId, req.HotelId)
+
+	return reviewComm(r), nil
+}
+
+// GetModerationQueue lists the reviews in a moderation status, oldest first.
+// Published reviews with images waiting for moderation are pending too.
+func (s *Server) GetModerationQueue(ctx context.Context, req *pb.QueueRequest) (*pb.Result, error) {
+	st := req.Status
+	if st == "" {
+		st = StatusPending
+	}
+	if st != StatusPending && st != StatusApproved && st != StatusRejected {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown status %q", st)
+	}
+	limit := int64(req.Limit)
+	if limit <= 0 {
+		limit = defaultQueueLimit
+	} else if limit > maxQueueLimit {
+		limit = maxQueueLimit
+	}
+
+	filter := bson.M{"status": st}
+	if st == StatusPending {
+		filter = bson.M{"$or": bson.A{
+			bson.M{"status": StatusPending},
+			bson.M{"status": bson.M{"$in": bson.A{StatusApproved, nil}}, "images.pending": true},
+		}}
+	}
+	if req.HotelId != "" {
+		filter["hotelId"] = req.HotelId
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}).SetLimit(limit)
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_moderation_queue", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	var helpers []ReviewHelper
+	curr, err := s.MongoClient.Database("review-db").Collection("reviews").Find(ctx, filter, opts)
+	if err == nil {
+		err = curr.All(ctx, &helpers)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to get %v reviews: %v", st, err)
+		return nil, status.Error(codes.Internal, "failed to get reviews")
+	}
+
+	res := &pb.Result{Reviews: make([]*pb.ReviewComm, 0, len(helpers))}
+	for _, h := range helpers {
+		res.Reviews = append(res.Reviews, reviewComm(h))
+	}
+	return res, nil
+}
+
+// ModerateReview approves or rejects a review. Approving it publishes its
+// pending images as well. The cached reviews of the hotel are dropped
+// whenever the published ones change.
+func (s *Server) ModerateReview(ctx context.Context, req *pb.ModerateRequest) (*pb.ReviewComm, error) {
+	if req.ReviewId == "" {
+		return nil, status.Error(codes.InvalidArgument, "reviewId is required")
+	}
+	if req.Status != StatusApproved && req.Status != StatusRejected {
+		return nil, status.Errorf(codes.InvalidArgument, "status must be %s or %s", StatusApproved, StatusRejected)
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":         req.Status,
+		"moderatedBy":    auth.FromContext(ctx).Username,
+		"moderatedAt":    time.Now().UTC().Truncate(time.Millisecond),
+		"moderationNote": req.Note,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	// the status only changes if the review is in another one, so that of
+	// concurrent moderations only one counts the review in the ratings
+	same := bson.A{req.Status}
+	if req.Status == StatusApproved {
+		same = append(same, nil)
+	}
+	filter := bson.M{"reviewId": req.ReviewId, "status": bson.M{"$nin": same}}
+	c := s.MongoClient.Database("review-db").Collection("reviews")
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_moderate_review", trace.WithSpanKind(trace.SpanKindClient))
+	var prev ReviewHelper
+	err := c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&prev)
+	changed := err == nil
+	if err == mongo.ErrNoDocuments {
+		// already in the status, only the note changes
+		err = c.FindOneAndUpdate(ctx, bson.M{"reviewId": req.ReviewId}, update, opts).Decode(&prev)
+	}
+	span.End()
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "review %s not found", req.ReviewId)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to moderate review [%v]: %v", req.ReviewId, err)
+		return nil, status.Error(codes.Internal, "failed to moderate review")
+	}
+	if req.Status == StatusApproved {
+		_, err = c.UpdateOne(ctx,
+			bson.M{"reviewId": req.ReviewId, "images.pending": true},
+			bson.M{"$unset": bson.M{"images.$[].pending": ""}},
+		)
+		if err != nil {
+			log.Error().Msgf("Failed to publish the images of review [%v]: %v", req.ReviewId, err)
+			return nil, status.Error(codes.Internal, "failed to moderate review")
+		}
+	}
+
+	if changed && published(prev.Status) != (req.Status == StatusApproved) {
+		delta := int64(1)
+		if req.Status != StatusApproved {
+			delta = -1
+		}
+		s.updateRating(ctx, prev, delta)
+	}
+	if published(prev.Status) || req.Status == StatusApproved {
+		s.invalidateReviews(ctx, prev.HotelId)
+	}
+
+	r := prev
+	r.Status = req.Status
+	r.ModerationNote = req.Note
+	if req.Status == StatusApproved {
+		for i := range r.Images {
+			r.Images[i].Pending = false
+		}
+	}
+	return reviewComm(r), nil
+}
@@ -19,14 +19,40 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Review_GetReviews_FullMethodName = "/review.Review/GetReviews"
+	Review_GetReviews_FullMethodName         = "/review.Review/GetReviews"
+	Review_SubmitReview_FullMethodName       = "/review.Review/SubmitReview"
+	Review_GetModerationQueue_FullMethodName = "/review.Review/GetModerationQueue"
+	Review_ModerateReview_FullMethodName     = "/review.Review/ModerateReview"
+	Review_GetRatingSummary_FullMethodName   = "/review.Review/GetRatingSummary"
+	Review_AddImages_FullMethodName          = "/review.Review/AddImages"
+	Review_VoteHelpful_FullMethodName        = "/review.Review/VoteHelpful"
+	Review_ReplyToReview_FullMethodName      = "/review.Review/ReplyToReview"
 )
 
 // ReviewClient is the client API for Review service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ReviewClient interface {
+	// GetReviews returns a page of the published reviews of a hotel.
 	GetReviews(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// SubmitReview adds a review of a hotel by the calling user, who must have
+	// stayed there. The review is only published once a moderator approves it.
+	SubmitReview(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*ReviewComm, error)
+	// GetModerationQueue lists the reviews in a moderation status, oldest first.
+	GetModerationQueue(ctx context.Context, in *QueueRequest, opts ...grpc.CallOption) (*Result, error)
+	// ModerateReview approves or rejects a review.
+	ModerateReview(ctx context.Context, in *ModerateRequest, opts ...grpc.CallOption) (*ReviewComm, error)
+	// GetRatingSummary returns the rating aggregates of the published reviews
+	// of hotels, in the order of the request.
+	GetRatingSummary(ctx context.Context, in *SummaryRequest, opts ...grpc.CallOption) (*SummaryResult, error)
+	// AddImages attaches uploaded images to a review of the calling user.
+	AddImages(ctx context.Context, in *AddImagesRequest, opts ...grpc.CallOption) (*ReviewComm, error)
+	// VoteHelpful marks a published review as helpful for the calling user,
+	// or withdraws the vote. Users have one vote per review.
+	VoteHelpful(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*ReviewComm, error)
+	// ReplyToReview posts the public reply of the hotel to a published
+	// review. A review has at most one reply.
+	ReplyToReview(ctx context.Context, in *ReplyRequest, opts ...grpc.CallOption) (*ReviewComm, error)
 }
 
 type reviewClient struct {
@@ -46,11 +72,93 @@ func (c *reviewClient) GetReviews(ctx context.Context, in *Request, opts ...grpc
 	return out, nil
 }
 
+func (c *reviewClient) SubmitReview(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*ReviewComm, error) {
+	out := new(ReviewComm)
+	err := c.cc.Invoke(ctx, Review_SubmitReview_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) GetModerationQueue(ctx context.Context, in *QueueRequest, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, Review_GetModerationQueue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) ModerateReview(ctx context.Context, in *ModerateRequest, opts ...grpc.CallOption) (*ReviewComm, error) {
+	out := new(ReviewComm)
+	err := c.cc.Invoke(ctx, Review_ModerateReview_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) GetRatingSummary(ctx context.Context, in *SummaryRequest, opts ...grpc.CallOption) (*SummaryResult, error) {
+	out := new(SummaryResult)
+	err := c.cc.Invoke(ctx, Review_GetRatingSummary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) AddImages(ctx context.Context, in *AddImagesRequest, opts ...grpc.CallOption) (*ReviewComm, error) {
+	out := new(ReviewComm)
+	err := c.cc.Invoke(ctx, Review_AddImages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) VoteHelpful(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*ReviewComm, error) {
+	out := new(ReviewComm)
+	err := c.cc.Invoke(ctx, Review_VoteHelpful_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewClient) ReplyToReview(ctx context.Context, in *ReplyRequest, opts ...grpc.CallOption) (*ReviewComm, error) {
+	out := new(ReviewComm)
+	err := c.cc.Invoke(ctx, Review_ReplyToReview_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ReviewServer is the server API for Review service.
 // All implementations must embed UnimplementedReviewServer
 // for forward compatibility
 type ReviewServer interface {
+	// GetReviews returns a page of the published reviews of a hotel.
 	GetReviews(context.Context, *Request) (*Result, error)
+	// SubmitReview adds a review of a hotel by the calling user, who must have
+	// stayed there. The review is only published once a moderator approves it.
+	SubmitReview(context.Context, *SubmitRequest) (*ReviewComm, error)
+	// GetModerationQueue lists the reviews in a moderation status, oldest first.
+	GetModerationQueue(context.Context, *QueueRequest) (*Result, error)
+	// ModerateReview approves or rejects a review.
+	ModerateReview(context.Context, *ModerateRequest) (*ReviewComm, error)
+	// GetRatingSummary returns the rating aggregates of the published reviews
+	// of hotels, in the order of the request.
+	GetRatingSummary(context.Context, *SummaryRequest) (*SummaryResult, error)
+	// AddImages attaches uploaded images to a review of the calling user.
+	AddImages(context.Context, *AddImagesRequest) (*ReviewComm, error)
+	// VoteHelpful marks a published review as helpful for the calling user,
+	// or withdraws the vote. Users have one vote per review.
+	VoteHelpful(context.Context, *VoteRequest) (*ReviewComm, error)
+	// ReplyToReview posts the public reply of the hotel to a published
+	// review. A review has at most one reply.
+	ReplyToReview(context.Context, *ReplyRequest) (*ReviewComm, error)
 	mustEmbedUnimplementedReviewServer()
 }
 
@@ -61,6 +169,27 @@ type UnimplementedReviewServer struct {
 func (UnimplementedReviewServer) GetReviews(context.Context, *Request) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetReviews not implemented")
 }
+func (UnimplementedReviewServer) SubmitReview(context.Context, *SubmitRequest) (*ReviewComm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitReview not implemented")
+}
+func (UnimplementedReviewServer) GetModerationQueue(context.Context, *QueueRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModerationQueue not implemented")
+}
+func (UnimplementedReviewServer) ModerateReview(context.Context, *ModerateRequest) (*ReviewComm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedReviewServer) GetRatingSummary(context.Context, *SummaryRequest) (*SummaryResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRatingSummary not implemented")
+}
+func (UnimplementedReviewServer) AddImages(context.Context, *AddImagesRequest) (*ReviewComm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddImages not implemented")
+}
+func (UnimplementedReviewServer) VoteHelpful(context.Context, *VoteRequest) (*ReviewComm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteHelpful not implemented")
+}
+func (UnimplementedReviewServer) ReplyToReview(context.Context, *ReplyRequest) (*ReviewComm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplyToReview not implemented")
+}
 func (UnimplementedReviewServer) mustEmbedUnimplementedReviewServer() {}
 
 // UnsafeReviewServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +221,132 @@ func _Review_GetReviews_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Review_SubmitReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).SubmitReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Review_SubmitReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).SubmitReview(ctx, req.(*SubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_GetModerationQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).GetModerationQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Review_GetModerationQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).GetModerationQueue(ctx, req.(*QueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Review_ModerateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).ModerateReview(ctx, req.(*ModerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_GetRatingSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).GetRatingSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Review_GetRatingSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).GetRatingSummary(ctx, req.(*SummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_AddImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).AddImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Review_AddImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).AddImages(ctx, req.(*AddImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_VoteHelpful_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).VoteHelpful(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Review_VoteHelpful_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).VoteHelpful(ctx, req.(*VoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Review_ReplyToReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServer).ReplyToReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Review_ReplyToReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServer).ReplyToReview(ctx, req.(*ReplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Review_ServiceDesc is the grpc.ServiceDesc for Review service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +358,34 @@ var Review_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetReviews",
 			Handler:    _Review_GetReviews_Handler,
 		},
+		{
+			MethodName: "SubmitReview",
+			Handler:    _Review_SubmitReview_Handler,
+		},
+		{
+			MethodName: "GetModerationQueue",
+			Handler:    _Review_GetModerationQueue_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _Review_ModerateReview_Handler,
+		},
+		{
+			MethodName: "GetRatingSummary",
+			Handler:    _Review_GetRatingSummary_Handler,
+		},
+		{
+			MethodName: "AddImages",
+			Handler:    _Review_AddImages_Handler,
+		},
+		{
+			MethodName: "VoteHelpful",
+			Handler:    _Review_VoteHelpful_Handler,
+		},
+		{
+			MethodName: "ReplyToReview",
+			Handler:    _Review_ReplyToReview_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/review/proto/review.proto",
@@ -0,0 +1,203 @@
+package review
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "hotelReservation/services/review/proto"
+)
+
+// ratingSummary aggregates the ratings of the published reviews of a hotel.
+// It is kept in review-db.ratings and updated as reviews are published or
+// withdrawn, rather than computed from the reviews.
+type ratingSummary struct {
+	HotelId string  `bson:"hotelId" json:"hotelId"`
+	Count   int64   `bson:"count" json:"count"`
+	Sum     float64 `bson:"sum" json:"sum"`
+	// Histogram counts the reviews by star, from "1" to "5".
+	Histogram map[string]int64 `bson:"histogram" json:"histogram"`
+}
+
+func (r ratingSummary) proto() *pb.RatingSummary {
+	s := &pb.RatingSummary{
+		HotelId:   r.HotelId,
+		Count:     r.Count,
+		Histogram: make([]int64, maxRating),
+	}
+	if r.Count > 0 {
+		s.Average = float32(r.Sum / float64(r.Count))
+	}
+	for i := range s.Histogram {
+		s.Histogram[i] = r.Histogram[strconv.Itoa(i+minRating)]
+	}
+	return s
+}
+
+// star rounds a rating to the nearest star of the histogram.
+func star(rating float32) int {
+	s := int(math.Round(float64(rating)))
+	if s < minRating {
+		return minRating
+	}
+	if s > maxRating {
+		return maxRating
+	}
+	return s
+}
+
+// ratingKey is the key of the cached rating summary of a hotel. Summaries
+// are cached under the generation of the reviews of the hotel, so that a
+// summary read from review-db before an update and cached after it is not
+// found once the update starts a new generation.
+func ratingKey(hotelId, gen string) string {
+	return "rating_" + hotelId + "_" + gen
+}
+
+func ratingsCollection(c *mongo.Client) *mongo.Collection {
+	return c.Database("review-db").Collection("ratings")
+}
+
+// updateRating adds a review to the rating summary of its hotel, or
+// removes it with a delta of -1.
+func (s *Server) updateRating(ctx context.Context, r ReviewHelper, delta int64) {
+	update := bson.M{"$inc": bson.M{
+		"count": delta,
+		"sum":   float64(delta) * float64(r.Rating),
+		"histogram." + strconv.Itoa(star(r.Rating)): delta,
+	}}
+
+	ctx, span := s.Tracer.Start(ctx, "mongo_update_rating", trace.WithSpanKind(trace.SpanKindClient))
+	_, err := ratingsCollection(s.MongoClient).UpdateOne(ctx, bson.M{"hotelId": r.HotelId}, update, options.Update().SetUpsert(true))
+	span.End()
+	if err != nil {
+		log.Error().Msgf("Failed to update rating summary of hotel [%v] with review [%v]: %v", r.HotelId, r.ReviewId, err)
+	}
+
+	s.invalidateReviews(ctx, r.HotelId)
+}
+
+// GetRatingSummary returns the rating aggregates of hotels. Hotels without
+// published reviews have an empty summary.
+func (s *Server) GetRatingSummary(ctx context.Context, req *pb.SummaryRequest) (*pb.SummaryResult, error) {
+	summaries := make(map[string]ratingSummary, len(req.HotelIds))
+
+	ctx, span := s.Tracer.Start(ctx, "memcached_get_multi_rating", trace.WithSpanKind(trace.SpanKindClient))
+	gens := s.reviewsGenerations(req.HotelIds)
+	keys := make([]string, 0, len(req.HotelIds))
+	for _, hotelId := range req.HotelIds {
+		keys = append(keys, ratingKey(hotelId, gens[hotelId]))
+	}
+	items, err := s.MemcClient.GetMulti(keys)
+	span.End()
+	if err != nil {
+		log.Warn().Msgf("Failed to get rating summaries from memcached: %v", err)
+	}
+
+	misses := make([]string, 0)
+	for _, hotelId := range req.HotelIds {
+		var r ratingSummary
+		if item, ok := items[ratingKey(hotelId, gens[hotelId])]; ok && json.Unmarshal(item.Value, &r) == nil {
+			summaries[hotelId] = r
+		} else if _, ok := summaries[hotelId]; !ok {
+			summaries[hotelId] = ratingSummary{HotelId: hotelId}
+			misses = append(misses, hotelId)
+		}
+	}
+
+	if len(misses) > 0 {
+		ctx, span := s.Tracer.Start(ctx, "mongo_rating", trace.WithSpanKind(trace.SpanKindClient))
+		var found []ratingSummary
+		curr, err := ratingsCollection(s.MongoClient).Find(ctx, bson.M{"hotelId": bson.M{"$in": misses}})
+		if err == nil {
+			err = curr.All(ctx, &found)
+		}
+		span.End()
+		if err != nil {
+			log.Error().Msgf("Failed to get rating summaries: %v", err)
+			return nil, status.Error(codes.Internal, "failed to get rating summaries")
+		}
+
+		for _, r := range found {
+			summaries[r.HotelId] = r
+		}
+		// hotels without reviews are cached too, until their first one
+		for _, hotelId := range misses {
+			b, err := json.Marshal(summaries[hotelId])
+			if err != nil {
+				log.Error().Msgf("Failed to marshal rating summary of hotel [%v]: %v", hotelId, err)
+				continue
+			}
+			s.MemcClient.Set(&memcache.Item{Key: ratingKey(hotelId, gens[hotelId]), Value: b})
+		}
+	}
+
+	res := &pb.SummaryResult{Summaries: make([]*pb.RatingSummary, 0, len(req.HotelIds))}
+	for _, hotelId := range req.HotelIds {
+		res.Summaries = append(res.Summaries, summaries[hotelId].proto())
+	}
+	return res, nil
+}
+
+// BackfillRatings builds the rating summaries of the published reviews when
+// there are none yet, such as on the first start after seeding the
+// reviews. Later changes are applied by moderation.
+func BackfillRatings(ctx context.Context, c *mongo.Client) error {
+	ratings := ratingsCollection(c)
+	_, err := ratings.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hotelId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	n, err := ratings.EstimatedDocumentCount(ctx)
+	if err != nil || n > 0 {
+		return err
+	}
+
+	curr, err := c.Database("review-db").Collection("reviews").Find(ctx, bson.M{"status": bson.M{"$in": bson.A{StatusApproved, nil}}})
+	if err != nil {
+		return err
+	}
+	var reviews []ReviewHelper
+	if err := curr.All(ctx, &reviews); err != nil {
+		return err
+	}
+
+	summaries := make(map[string]*ratingSummary)
+	for _, r := range reviews {
+		sum, ok := summaries[r.HotelId]
+		if !ok {
+			sum = &ratingSummary{HotelId: r.HotelId, Histogram: make(map[string]int64)}
+			summaries[r.HotelId] = sum
+		}
+		sum.Count++
+		sum.Sum += float64(r.Rating)
+		sum.Histogram[strconv.Itoa(star(r.Rating))]++
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(summaries))
+	for _, sum := range summaries {
+		docs = append(docs, sum)
+	}
+	// another replica may be backfilling at the same time
+	_, err = ratings.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
@@ -1,11 +1,14 @@
 package review
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	// "io/ioutil"
 	"net"
@@ -22,8 +25,13 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	"hotelReservation/dialer"
 	"hotelReservation/registry"
+	reservation "hotelReservation/services/reservation/proto"
 	pb "hotelReservation/services/review/proto"
 	"hotelReservation/tls"
 
@@ -46,6 +54,11 @@ type Server struct {
 	Registry       *registry.Client
 	MemcClient     *memcache.Client
 	uuid           string
+
+	reservationClient reservation.ReservationClient
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -65,6 +78,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			otelgrpc.StreamServerInterceptor(
@@ -99,6 +113,11 @@ func (s *Server) Run() error {
 	
 	pb.RegisterReviewServer(srv, s)
 
+	// if err := s.initReservationClient(context.Background(), "srv-reservation"); err != nil {
+	if err := s.initReservationClient(context.Background(), "reservation-hotel-hotelres:8087"); err != nil {
+		return err
+	}
+
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.Port))
 	if err != nil {
 		log.Fatal().Msgf("failed to listen: %v", err)
@@ -118,82 +137,279 @@ func (s *Server) Shutdown() {
 	s.Registry.Deregister(s.uuid)
 }
 
+func (s *Server) initReservationClient(ctx context.Context, name string) error {
+	conn, err := dialer.Dial(name, ctx, s.TracerProvider, dialer.WithBalancer(s.Registry.Client))
+	if err != nil {
+		return fmt.Errorf("dialer error: %v", err)
+	}
+	s.reservationClient = reservation.NewReservationClient(conn)
+	return nil
+}
+
 type ReviewHelper struct {
-	ReviewId    string    `bson:"reviewId"`
-	HotelId     string    `bson:"hotelId"`
-	Name        string    `bson:"name"`
-	Rating      float32   `bson:"rating"`
-	Description string    `bson:"description"`
-	Image       *pb.Image `bson:"images"`
+	ReviewId    string        `bson:"reviewId"`
+	HotelId     string        `bson:"hotelId"`
+	Name        string        `bson:"name"`
+	Rating      float32       `bson:"rating"`
+	Description string        `bson:"description"`
+	Images      []ImageHelper `bson:"images"`
+
+	// Username is the author of a submitted review.
+	Username       string    `bson:"username,omitempty"`
+	Status         string    `bson:"status,omitempty"`
+	CreatedAt      time.Time `bson:"createdAt,omitempty"`
+	ModeratedBy    string    `bson:"moderatedBy,omitempty"`
+	ModeratedAt    time.Time `bson:"moderatedAt,omitempty"`
+	ModerationNote string    `bson:"moderationNote,omitempty"`
+	HelpfulCount   int64     `bson:"helpfulCount"`
+
+	// Reply is the reply of the hotel, if any.
+	Reply *ReplyHelper `bson:"reply,omitempty"`
 }
 
 type ImageHelper struct {
 	Url     string `bson:"url"`
 	Default bool   `bson:"default"`
+
+	// Uploaded images keep their metadata and the review they belong to.
+	Id           string `bson:"id,omitempty"`
+	ThumbnailUrl string `bson:"thumbnailUrl,omitempty"`
+	ContentType  string `bson:"contentType,omitempty"`
+	Width        int32  `bson:"width,omitempty"`
+	Height       int32  `bson:"height,omitempty"`
+	Size         int64  `bson:"size,omitempty"`
+	ReviewId     string `bson:"reviewId,omitempty"`
+	HotelId      string `bson:"hotelId,omitempty"`
+	UploadedBy   string `bson:"uploadedBy,omitempty"`
+	// Pending images are published once a moderator approves their review.
+	Pending bool `bson:"pending,omitempty"`
 }
 
-func (s *Server) GetReviews(ctx context.Context, req *pb.Request) (*pb.Result, error) {
+// The orders of GetReviews, each ending with the review ID so that pages
+// do not overlap.
+var reviewSorts = map[string]bson.D{
+	"newest":       {{Key: "createdAt", Value: -1}, {Key: "reviewId", Value: -1}},
+	"highest":      {{Key: "rating", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "reviewId", Value: -1}},
+	"lowest":       {{Key: "rating", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "reviewId", Value: -1}},
+	"most_helpful": {{Key: "helpfulCount", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "reviewId", Value: -1}},
+}
 
-	res := new(pb.Result)
-	reviews := make([]*pb.ReviewComm, 0)
+const (
+	defaultPageSize = 10
+	maxPageSize     = 50
+)
 
+// GetReviews returns a page of the published reviews of a hotel. Pages are
+// cached one by one under the current generation of the reviews of the
+// hotel, which moderation replaces to drop them all at once.
+func (s *Server) GetReviews(ctx context.Context, req *pb.Request) (*pb.Result, error) {
 	hotelId := req.HotelId
+	if hotelId == "" {
+		return nil, status.Error(codes.InvalidArgument, "hotelId is required")
+	}
+	sort := req.Sort
+	if sort == "" {
+		sort = "newest"
+	}
+	order, ok := reviewSorts[sort]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown sort %q", req.Sort)
+	}
+	pageSize := int64(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	} else if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	cursor, err := decodeCursor(req.Cursor, sort)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+	}
 
 	ctx, span := s.Tracer.Start(ctx, "memcached_get_review", trace.WithSpanKind(trace.SpanKindClient))
-	item, err := s.MemcClient.Get(hotelId)
+	key := fmt.Sprintf("reviews_%s_%s_%s_%g_%d_%s", hotelId, s.reviewsGeneration(hotelId), sort, req.MinRating, pageSize, req.Cursor)
+	item, err := s.MemcClient.Get(key)
 	span.End()
-	if err != nil && err != memcache.ErrCacheMiss {
-		log.Panic().Msgf("Tried to get hotelId [%v], but got memmcached error = %s", hotelId, err)
-	} else {
-		if err == memcache.ErrCacheMiss {
-			_, span := s.Tracer.Start(ctx, "mongo_review", trace.WithSpanKind(trace.SpanKindClient))
-
-			defer span.End()
-			//session := s.MongoSession.Copy()
-			//defer session.Close()
-			//c := session.DB("review-db").C("reviews")
-			c := s.MongoClient.Database("review-db").Collection("reviews")
-
-			curr, err := c.Find(context.TODO(), bson.M{"hotelId": hotelId})
-			if err != nil {
-				log.Error().Msgf("Failed get reviews: ", err)
-			}
-
-			var reviewHelpers []ReviewHelper
-			//err = c.Find(bson.M{"hotelId": hotelId}).All(&reviewHelpers)
-			curr.All(context.TODO(), &reviewHelpers)
-			if err != nil {
-				log.Error().Msgf("Failed get hotels data: ", err)
-			}
-
-			for _, reviewHelper := range reviewHelpers {
-				revComm := pb.ReviewComm{
-					ReviewId:    reviewHelper.ReviewId,
-					Name:        reviewHelper.Name,
-					Rating:      reviewHelper.Rating,
-					Description: reviewHelper.Description,
-					Images:      reviewHelper.Image}
-				reviews = append(reviews, &revComm)
-			}
-
-			reviewJson, err := json.Marshal(reviews)
-			if err != nil {
-				log.Error().Msgf("Failed to marshal hotel [id: %v] with err:", hotelId, err)
-			}
-			memcStr := string(reviewJson)
-
-			s.MemcClient.Set(&memcache.Item{Key: hotelId, Value: []byte(memcStr)})
-		} else {
-			reviewsStr := string(item.Value)
-			log.Trace().Msgf("memc hit with %v", reviewsStr)
-			if err := json.Unmarshal([]byte(reviewsStr), &reviews); err != nil {
-				log.Panic().Msgf("Failed to unmarshal reviews: %s", err)
-			}
+	if err == nil {
+		res := new(pb.Result)
+		log.Trace().Msgf("memc hit with %v", string(item.Value))
+		if err := json.Unmarshal(item.Value, res); err == nil {
+			return res, nil
 		}
+		log.Error().Msgf("Failed to unmarshal reviews page [%v]: %v", key, err)
+	} else if err != memcache.ErrCacheMiss {
+		log.Error().Msgf("Tried to get reviews page [%v], but got memmcached error = %s", key, err)
 	}
 
-	//reviewsEmpty := make([]*pb.ReviewComm, 0)
+	filter := publishedFilter(hotelId)
+	if req.MinRating > 0 {
+		filter["rating"] = bson.M{"$gte": req.MinRating}
+	}
+	if cursor != nil {
+		filter["$and"] = bson.A{cursor.after(order)}
+	}
+	// one more than the page tells if there is a next one
+	opts := options.Find().SetSort(order).SetLimit(pageSize + 1)
+
+	ctx, span = s.Tracer.Start(ctx, "mongo_review", trace.WithSpanKind(trace.SpanKindClient))
+	var reviewHelpers []ReviewHelper
+	curr, err := s.MongoClient.Database("review-db").Collection("reviews").Find(ctx, filter, opts)
+	if err == nil {
+		err = curr.All(ctx, &reviewHelpers)
+	}
+	span.End()
+	if err != nil {
+		log.Error().Msgf("Failed get reviews of hotel [%v]: %v", hotelId, err)
+		return nil, status.Error(codes.Internal, "failed to get reviews")
+	}
+
+	res := &pb.Result{Reviews: make([]*pb.ReviewComm, 0, len(reviewHelpers))}
+	if int64(len(reviewHelpers)) > pageSize {
+		reviewHelpers = reviewHelpers[:pageSize]
+		res.NextCursor = encodeCursor(sort, reviewHelpers[pageSize-1])
+	}
+	for _, reviewHelper := range reviewHelpers {
+		res.Reviews = append(res.Reviews, publishedComm(reviewHelper))
+	}
+
+	if b, err := json.Marshal(res); err != nil {
+		log.Error().Msgf("Failed to marshal reviews of hotel [id: %v]: %v", hotelId, err)
+	} else {
+		s.MemcClient.Set(&memcache.Item{Key: key, Value: b})
+	}
 
-	res.Reviews = reviews
 	return res, nil
 }
+
+// pageCursor is where a page of reviews ends: the sort keys of its last
+// review. The next page starts after it, so that reviews published or
+// withdrawn in the meantime do not shift the pages.
+type pageCursor struct {
+	Sort         string  `json:"s"`
+	Rating       float32 `json:"r,omitempty"`
+	HelpfulCount int64   `json:"h,omitempty"`
+	// CreatedAt is in unix milliseconds, the precision MongoDB stores.
+	CreatedAt int64  `json:"t"`
+	ReviewId  string `json:"id"`
+}
+
+// encodeCursor returns the opaque cursor of the page after review r.
+func encodeCursor(sort string, r ReviewHelper) string {
+	b, _ := json.Marshal(pageCursor{
+		Sort:         sort,
+		Rating:       r.Rating,
+		HelpfulCount: r.HelpfulCount,
+		CreatedAt:    r.CreatedAt.UnixMilli(),
+		ReviewId:     r.ReviewId,
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor returns the cursor of a page in sort, or nil for the first
+// page.
+func decodeCursor(cursor, sort string) (*pageCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	c := new(pageCursor)
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	if c.Sort != sort || c.ReviewId == "" {
+		return nil, fmt.Errorf("cursor of another sort %q", c.Sort)
+	}
+	return c, nil
+}
+
+// after returns the filter of the reviews after the cursor in order: those
+// with the same first keys and a later next one.
+func (c *pageCursor) after(order bson.D) bson.M {
+	values := map[string]interface{}{
+		"rating":       c.Rating,
+		"helpfulCount": c.HelpfulCount,
+		"createdAt":    time.UnixMilli(c.CreatedAt).UTC(),
+		"reviewId":     c.ReviewId,
+	}
+	or := make(bson.A, 0, len(order))
+	for i, k := range order {
+		cond := bson.M{}
+		for _, prev := range order[:i] {
+			cond[prev.Key] = values[prev.Key]
+		}
+		op := "$gt"
+		if k.Value.(int) < 0 {
+			op = "$lt"
+		}
+		cond[k.Key] = bson.M{op: values[k.Key]}
+		or = append(or, cond)
+	}
+	return bson.M{"$or": or}
+}
+
+func generationKey(hotelId string) string {
+	return "reviews_gen_" + hotelId
+}
+
+// reviewsGeneration returns the generation the cached pages of the
+// reviews of a hotel belong to. Generations are start times rather than
+// counters, so that an evicted generation does not come back and bring
+// its stale pages with it.
+func (s *Server) reviewsGeneration(hotelId string) string {
+	item, err := s.MemcClient.Get(generationKey(hotelId))
+	if err == nil {
+		return string(item.Value)
+	}
+	if err != memcache.ErrCacheMiss {
+		log.Error().Msgf("Failed to get reviews generation of hotel [%v]: %v", hotelId, err)
+	}
+
+	gen := strconv.FormatInt(time.Now().UnixNano(), 36)
+	err = s.MemcClient.Add(&memcache.Item{Key: generationKey(hotelId), Value: []byte(gen)})
+	if err == memcache.ErrNotStored {
+		// started by a concurrent request
+		if item, err := s.MemcClient.Get(generationKey(hotelId)); err == nil {
+			return string(item.Value)
+		}
+	}
+	return gen
+}
+
+// reviewsGenerations returns the generations of the reviews of hotels, as
+// reviewsGeneration does, with one memcached request for the hotels that
+// have one.
+func (s *Server) reviewsGenerations(hotelIds []string) map[string]string {
+	keys := make([]string, 0, len(hotelIds))
+	for _, hotelId := range hotelIds {
+		keys = append(keys, generationKey(hotelId))
+	}
+	items, err := s.MemcClient.GetMulti(keys)
+	if err != nil {
+		log.Error().Msgf("Failed to get reviews generations: %v", err)
+	}
+
+	gens := make(map[string]string, len(hotelIds))
+	for _, hotelId := range hotelIds {
+		if item, ok := items[generationKey(hotelId)]; ok {
+			gens[hotelId] = string(item.Value)
+		} else if _, ok := gens[hotelId]; !ok {
+			gens[hotelId] = s.reviewsGeneration(hotelId)
+		}
+	}
+	return gens
+}
+
+// invalidateReviews drops the cached pages and rating summary of the
+// reviews of a hotel by starting a new generation.
+func (s *Server) invalidateReviews(ctx context.Context, hotelId string) {
+	_, span := s.Tracer.Start(ctx, "memcached_invalidate_review", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	gen := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := s.MemcClient.Set(&memcache.Item{Key: generationKey(hotelId), Value: []byte(gen)}); err != nil {
+		log.Error().Msgf("Failed to invalidate reviews of hotel [%v]: %v", hotelId, err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
+	"hotelReservation/auth"
 	"hotelReservation/dialer"
 	"hotelReservation/registry"
 	geo "hotelReservation/services/geo/proto"
@@ -39,6 +40,9 @@ type Server struct {
 	ConsulAddr     string
 	KnativeDns     string
 	Registry       *registry.Client
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -57,6 +61,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			otelgrpc.StreamServerInterceptor(
@@ -0,0 +1,239 @@
+package user
+
+import (
+	"regexp"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	pb "hotelReservation/services/user/proto"
+)
+
+// minPasswordLength is the shortest password Register and ChangePassword
+// accept.
+const minPasswordLength = 8
+
+var validUsername = regexp.MustCompile(`^[A-Za-z0-9_.@-]{3,64}$`)
+
+// Register creates a user with a unique username.
+func (s *Server) Register(ctx context.Context, req *pb.Request) (*pb.Result, error) {
+	log.Trace().Msg("Register")
+
+	if !validUsername.MatchString(req.Username) {
+		return nil, status.Error(codes.InvalidArgument, "username must be 3 to 64 letters, digits or _.@-")
+	}
+	if err := validatePassword(req.Password); err != nil {
+		return nil, err
+	}
+
+	hash, err := HashPassword(req.Password)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	user := User{Username: req.Username, Password: hash}
+	collection := s.MongoClient.Database("user-db").Collection("user")
+	if _, err := collection.InsertOne(ctx, user); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "username %v is taken", req.Username)
+		}
+		log.Error().Msgf("Failed to insert user %v: %v", req.Username, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.setUser(user)
+	return &pb.Result{Correct: true}, nil
+}
+
+// ChangePassword replaces the password of a user.
+func (s *Server) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.Result, error) {
+	log.Trace().Msg("ChangePassword")
+
+	if err := validatePassword(req.NewPassword); err != nil {
+		return nil, err
+	}
+	user, err := s.authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// only replace the hash that was checked, in case of a concurrent change
+	hash, err := HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	collection := s.MongoClient.Database("user-db").Collection("user")
+	res, err := collection.UpdateOne(ctx,
+		bson.M{"username": req.Username, "password": user.Password},
+		bson.M{"$set": bson.M{"password": hash}},
+	)
+	if err != nil {
+		log.Error().Msgf("Failed to update password of %v: %v", req.Username, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if res.MatchedCount == 0 {
+		return nil, status.Errorf(codes.Aborted, "password of %v was changed concurrently", req.Username)
+	}
+
+	user.Password = hash
+	s.setUser(user)
+	return &pb.Result{Correct: true}, nil
+}
+
+// DeleteUser removes a user.
+func (s *Server) DeleteUser(ctx context.Context, req *pb.Request) (*pb.Result, error) {
+	log.Trace().Msg("DeleteUser")
+
+	if _, err := s.authenticate(ctx, req.Username, req.Password); err != nil {
+		return nil, err
+	}
+
+	collection := s.MongoClient.Database("user-db").Collection("user")
+	if _, err := collection.DeleteOne(ctx, bson.M{"username": req.Username}); err != nil {
+		log.Error().Msgf("Failed to delete user %v: %v", req.Username, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.mu.Lock()
+	delete(s.users, req.Username)
+	s.mu.Unlock()
+	return &pb.Result{Correct: true}, nil
+}
+
+// SetRole changes the role of a user. Only admins may call it, as set in
+// the auth policy.
+func (s *Server) SetRole(ctx context.Context, req *pb.SetRoleRequest) (*pb.Result, error) {
+	log.Trace().Msg("SetRole")
+
+	if !auth.ValidRole(req.Role) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown role %q", req.Role)
+	}
+	if req.Role != auth.RoleHotelManager && len(req.Hotels) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "only hotel managers have hotels")
+	}
+
+	var user User
+	collection := s.MongoClient.Database("user-db").Collection("user")
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"username": req.Username},
+		bson.M{"$set": bson.M{"role": req.Role, "hotels": req.Hotels}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Errorf(codes.NotFound, "user %v not found", req.Username)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to set role of %v: %v", req.Username, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	log.Info().Msgf("User %v is now %v of %v", req.Username, req.Role, req.Hotels)
+	s.setUser(user)
+	return &pb.Result{Correct: true}, nil
+}
+
+// authenticate checks a password and returns the stored user. It fails
+// with Unauthenticated for unknown users and wrong passwords alike, and
+// with the errors of checkLogin for blocked logins.
+func (s *Server) authenticate(ctx context.Context, username, password string) (User, error) {
+	user, ok, err := s.checkLogin(ctx, username, password)
+	if err != nil {
+		return User{}, err
+	}
+	if !ok {
+		return User{}, status.Error(codes.Unauthenticated, "wrong username or password")
+	}
+	return user, nil
+}
+
+// verify checks a password against the users map. The map can trail
+// changes made through other replicas, so a miss is looked up in mongo, and
+// a mismatch is checked once more only if mongo holds another hash than the
+// map. A matching legacy or outdated hash is replaced by a current one.
+func (s *Server) verify(ctx context.Context, username, password string) (User, bool, error) {
+	s.mu.RLock()
+	cached, found := s.users[username]
+	s.mu.RUnlock()
+
+	if found {
+		if ok, rehash := checkPassword(cached.Password, password); ok {
+			if rehash {
+				cached = s.upgradeHash(ctx, cached, password)
+			}
+			return cached, true, nil
+		}
+	}
+
+	filter := bson.M{"username": username}
+	if found {
+		// only a changed hash can match now
+		filter["password"] = bson.M{"$ne": cached.Password}
+	}
+	var user User
+	collection := s.MongoClient.Database("user-db").Collection("user")
+	err := collection.FindOne(ctx, filter).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return User{}, false, nil
+	}
+	if err != nil {
+		log.Error().Msgf("Failed get user %v: %v", username, err)
+		return User{}, false, err
+	}
+	s.setUser(user)
+
+	ok, rehash := checkPassword(user.Password, password)
+	if !ok {
+		return user, false, nil
+	}
+	if rehash {
+		user = s.upgradeHash(ctx, user, password)
+	}
+	return user, true, nil
+}
+
+// upgradeHash replaces the stored hash of a user who just logged in with a
+// current one, and returns the user as now stored. Failures are logged
+// only; the old hash keeps working.
+func (s *Server) upgradeHash(ctx context.Context, user User, password string) User {
+	hash, err := HashPassword(password)
+	if err != nil {
+		log.Error().Msgf("Failed to rehash password of %v: %v", user.Username, err)
+		return user
+	}
+
+	collection := s.MongoClient.Database("user-db").Collection("user")
+	res, err := collection.UpdateOne(ctx,
+		bson.M{"username": user.Username, "password": user.Password},
+		bson.M{"$set": bson.M{"password": hash}},
+	)
+	if err != nil {
+		log.Error().Msgf("Failed to upgrade password hash of %v: %v", user.Username, err)
+		return user
+	}
+	if res.MatchedCount == 0 {
+		// changed meanwhile, keep whatever is stored now
+		return user
+	}
+
+	log.Trace().Msgf("Upgraded password hash of %v", user.Username)
+	user.Password = hash
+	s.setUser(user)
+	return user
+}
+
+func (s *Server) setUser(user User) {
+	s.mu.Lock()
+	s.users[user.Username] = user
+	s.mu.Unlock()
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return status.Errorf(codes.InvalidArgument, "password must be %d to %d bytes", minPasswordLength, maxPasswordLength)
+	}
+	return nil
+}
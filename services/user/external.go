@@ -0,0 +1,121 @@
+package user
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	"hotelReservation/auth/oidc"
+	pb "hotelReservation/services/user/proto"
+)
+
+// maxUsernameAttempts is how many usernames are tried for a new external
+// user before giving up.
+const maxUsernameAttempts = 10
+
+var invalidUsernameChars = regexp.MustCompile(`[^A-Za-z0-9_.@-]+`)
+
+// ExternalLogin verifies an ID token of the configured issuer, maps its
+// issuer and subject to a user, creating a guest on the first login, and
+// returns a session token.
+func (s *Server) ExternalLogin(ctx context.Context, req *pb.ExternalLoginRequest) (*pb.LoginResult, error) {
+	log.Trace().Msg("ExternalLogin")
+
+	if s.OIDC.Issuer == "" {
+		return nil, status.Error(codes.FailedPrecondition, "OpenID Connect login is not configured")
+	}
+	if req.IdToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "idToken must be set")
+	}
+	p, err := s.oidcProvider.Get(ctx, s.OIDC, nil)
+	if err != nil {
+		log.Error().Msgf("Failed to discover OpenID Connect issuer: %v", err)
+		return nil, status.Error(codes.Unavailable, "identity provider unavailable")
+	}
+	token, err := p.Verify(req.IdToken, req.Nonce, time.Now())
+	if err != nil {
+		log.Warn().Msgf("Rejected ID token: %v", err)
+		return nil, status.Error(codes.Unauthenticated, "invalid ID token")
+	}
+
+	user, err := s.findExternalUser(ctx, token.Issuer, token.Subject)
+	if err == mongo.ErrNoDocuments {
+		user, err = s.createExternalUser(ctx, token)
+	}
+	if err != nil {
+		log.Error().Msgf("Failed to get user of %v at %v: %v", token.Subject, token.Issuer, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.setUser(user)
+	return s.issueToken(user)
+}
+
+func (s *Server) findExternalUser(ctx context.Context, issuer, subject string) (User, error) {
+	var user User
+	collection := s.MongoClient.Database("user-db").Collection("user")
+	err := collection.FindOne(ctx, bson.M{"issuer": issuer, "subject": subject}).Decode(&user)
+	return user, err
+}
+
+// createExternalUser inserts a user for an issuer and subject under the
+// first free username derived from the claims. A concurrent first login of
+// the same user fails on the unique issuer and subject index, and then the
+// user it created is returned.
+func (s *Server) createExternalUser(ctx context.Context, token *oidc.IDToken) (User, error) {
+	base := externalUsername(token)
+	collection := s.MongoClient.Database("user-db").Collection("user")
+
+	for i := 1; i <= maxUsernameAttempts; i++ {
+		user := User{
+			Username: base,
+			Role:     auth.RoleGuest,
+			Issuer:   token.Issuer,
+			Subject:  token.Subject,
+		}
+		if i > 1 {
+			user.Username = fmt.Sprintf("%s_%d", base, i)
+		}
+
+		_, err := collection.InsertOne(ctx, user)
+		if err == nil {
+			log.Info().Msgf("Created user %v for %v at %v", user.Username, token.Subject, token.Issuer)
+			return user, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return User{}, err
+		}
+		if existing, err := s.findExternalUser(ctx, token.Issuer, token.Subject); err == nil {
+			return existing, nil
+		}
+	}
+	return User{}, fmt.Errorf("no free username for %v", base)
+}
+
+// externalUsername derives a username from the preferred_username claim,
+// else from the local part of the email claim.
+func externalUsername(token *oidc.IDToken) string {
+	name := token.PreferredUsername
+	if name == "" {
+		name, _, _ = strings.Cut(token.Email, "@")
+	}
+	name = invalidUsernameChars.ReplaceAllString(name, "_")
+	if len(name) > 48 {
+		name = name[:48]
+	}
+	for len(name) < 3 {
+		name += "_"
+	}
+	if name == "___" {
+		name = "user"
+	}
+	return name
+}
@@ -0,0 +1,196 @@
+package user
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+	pb "hotelReservation/services/user/proto"
+)
+
+const (
+	// tokenIssuer is the iss claim of session tokens.
+	tokenIssuer = "srv-user"
+
+	defaultTokenTTL    = time.Hour
+	defaultKeyRotation = 24 * time.Hour
+	keyCheckInterval   = time.Minute
+)
+
+// signingKey is a session token signing key. Keys are kept in user-db so
+// every replica signs with the same key and publishes the same key set.
+type signingKey struct {
+	Kid       string    `bson:"kid"`
+	Private   []byte    `bson:"private"`
+	Public    []byte    `bson:"public"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+// Login checks the password of a user and returns a session token signed
+// with the newest key.
+func (s *Server) Login(ctx context.Context, req *pb.Request) (*pb.LoginResult, error) {
+	log.Trace().Msg("Login")
+
+	user, err := s.authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueToken(user)
+}
+
+// issueToken returns a session token for user signed with the newest key.
+func (s *Server) issueToken(user User) (*pb.LoginResult, error) {
+	s.keyMu.RLock()
+	keys := s.keys
+	s.keyMu.RUnlock()
+	if len(keys) == 0 {
+		return nil, status.Error(codes.Unavailable, "no signing key")
+	}
+	key := keys[0]
+
+	now := time.Now()
+	claims := auth.Claims{
+		Issuer:    tokenIssuer,
+		Subject:   user.Username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.tokenTTL()).Unix(),
+		Role:      user.Role,
+		Hotels:    user.Hotels,
+	}
+	token, err := auth.Sign(claims, key.Kid, ed25519.PrivateKey(key.Private))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.LoginResult{Token: token, ExpiresAt: claims.ExpiresAt}, nil
+}
+
+// GetKeys returns the public keys of every token that may still be valid.
+func (s *Server) GetKeys(ctx context.Context, req *pb.KeysRequest) (*pb.KeySet, error) {
+	s.keyMu.RLock()
+	keys := s.keys
+	s.keyMu.RUnlock()
+
+	res := &pb.KeySet{Keys: make([]*pb.Key, len(keys))}
+	for i, key := range keys {
+		res.Keys[i] = &pb.Key{Kid: key.Kid, PublicKey: key.Public, CreatedAt: key.CreatedAt.Unix()}
+	}
+	return res, nil
+}
+
+// rotateKeysLoop checks every keyCheckInterval whether the signing key is
+// due for rotation until ctx is done.
+func (s *Server) rotateKeysLoop(ctx context.Context) {
+	ticker := time.NewTicker(keyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.rotateKeys(ctx); err != nil {
+				log.Error().Msgf("Failed to rotate signing keys: %v", err)
+			}
+		}
+	}
+}
+
+// rotateKeys adds a signing key when the newest one is older than
+// KeyRotation and drops the keys whose tokens have all expired.
+//
+// The key id names the rotation period, so replicas rotating at the same
+// time insert the same kid and all but the first fail on the unique index.
+func (s *Server) rotateKeys(ctx context.Context) error {
+	collection := s.MongoClient.Database("user-db").Collection("keys")
+	now := time.Now()
+
+	// a key signs tokens for one rotation period, which then live for up
+	// to a token TTL
+	expired := now.Add(-s.keyRotation() - s.tokenTTL())
+	if _, err := collection.DeleteMany(ctx, bson.M{"createdAt": bson.M{"$lt": expired}}); err != nil {
+		return err
+	}
+
+	keys, err := loadKeys(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 || now.Sub(keys[0].CreatedAt) >= s.keyRotation() {
+		key, err := newSigningKey(now.Truncate(s.keyRotation()))
+		if err != nil {
+			return err
+		}
+		if _, err := collection.InsertOne(ctx, key); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		log.Info().Msgf("Rotated session signing key, kid = %s", key.Kid)
+
+		if keys, err = loadKeys(ctx, collection); err != nil {
+			return err
+		}
+	}
+	if len(keys) == 0 {
+		return errors.New("no signing key")
+	}
+
+	s.keyMu.Lock()
+	s.keys = keys
+	s.keyMu.Unlock()
+	return nil
+}
+
+// loadKeys returns the signing keys, newest first.
+func loadKeys(ctx context.Context, collection *mongo.Collection) ([]signingKey, error) {
+	cur, err := collection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []signingKey
+	if err := cur.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func newSigningKey(period time.Time) (signingKey, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return signingKey{}, err
+	}
+	return signingKey{
+		Kid:       fmt.Sprintf("%x", period.Unix()),
+		Private:   private,
+		Public:    public,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func (s *Server) tokenTTL() time.Duration {
+	if s.TokenTTL <= 0 {
+		return defaultTokenTTL
+	}
+	return s.TokenTTL
+}
+
+func (s *Server) keyRotation() time.Duration {
+	if s.KeyRotation <= 0 {
+		return defaultKeyRotation
+	}
+	return s.KeyRotation
+}
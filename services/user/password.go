@@ -0,0 +1,53 @@
+package user
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordCost is the bcrypt cost of new password hashes. Hashes made with
+// a lower cost are upgraded on the next successful login.
+const passwordCost = 12
+
+// maxPasswordLength is the longest password bcrypt can hash.
+const maxPasswordLength = 72
+
+// HashPassword returns the stored form of a password: a bcrypt hash that
+// carries its own salt and cost.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// checkPassword reports whether password matches a stored hash, and whether
+// the hash should be replaced by a new one. Besides bcrypt hashes it accepts
+// the unsalted SHA-256 hex digests users were first stored with; those
+// always need replacing.
+func checkPassword(stored, password string) (ok, rehash bool) {
+	if isBcrypt(stored) {
+		if bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) != nil {
+			return false, false
+		}
+		cost, err := bcrypt.Cost([]byte(stored))
+		return true, err != nil || cost < passwordCost
+	}
+
+	want, err := hex.DecodeString(stored)
+	if err != nil || len(want) != sha256.Size {
+		return false, false
+	}
+	sum := sha256.Sum256([]byte(password))
+	ok = subtle.ConstantTimeCompare(sum[:], want) == 1
+	return ok, ok
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
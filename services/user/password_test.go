@@ -0,0 +1,69 @@
+package user
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCheckPassword(t *testing.T) {
+	sum := sha256.Sum256([]byte("secret"))
+	legacy := hex.EncodeToString(sum[:])
+	current, err := HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cheap, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		stored   string
+		password string
+		ok       bool
+		rehash   bool
+	}{
+		{"legacy sha256", legacy, "secret", true, true},
+		{"legacy sha256 wrong password", legacy, "Secret", false, false},
+		{"bcrypt", current, "secret", true, false},
+		{"bcrypt wrong password", current, "Secret", false, false},
+		{"bcrypt below current cost", string(cheap), "secret", true, true},
+		{"bcrypt below current cost wrong password", string(cheap), "Secret", false, false},
+		{"malformed hash", "not a hash", "secret", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash := checkPassword(tt.stored, tt.password)
+			if ok != tt.ok || rehash != tt.rehash {
+				t.Errorf("checkPassword = (%v, %v), want (%v, %v)", ok, rehash, tt.ok, tt.rehash)
+			}
+		})
+	}
+}
+
+// TestUpgradedHash checks that a hash replacing a legacy one, as made by
+// upgradeHash, verifies and needs no further upgrade.
+func TestUpgradedHash(t *testing.T) {
+	sum := sha256.Sum256([]byte("secret"))
+	if ok, rehash := checkPassword(hex.EncodeToString(sum[:]), "secret"); !ok || !rehash {
+		t.Fatalf("legacy hash: checkPassword = (%v, %v), want (true, true)", ok, rehash)
+	}
+
+	upgraded, err := HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isBcrypt(upgraded) {
+		t.Errorf("upgraded hash %q is not a bcrypt hash", upgraded)
+	}
+	if cost, _ := bcrypt.Cost([]byte(upgraded)); cost != passwordCost {
+		t.Errorf("upgraded hash cost = %d, want %d", cost, passwordCost)
+	}
+	if ok, rehash := checkPassword(upgraded, "secret"); !ok || rehash {
+		t.Errorf("upgraded hash: checkPassword = (%v, %v), want (true, false)", ok, rehash)
+	}
+}
@@ -19,7 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	User_CheckUser_FullMethodName = "/user.User/CheckUser"
+	User_CheckUser_FullMethodName      = "/user.User/CheckUser"
+	User_Register_FullMethodName       = "/user.User/Register"
+	User_ChangePassword_FullMethodName = "/user.User/ChangePassword"
+	User_DeleteUser_FullMethodName     = "/user.User/DeleteUser"
+	User_Login_FullMethodName          = "/user.User/Login"
+	User_GetKeys_FullMethodName        = "/user.User/GetKeys"
+	User_SetRole_FullMethodName        = "/user.User/SetRole"
+	User_ExternalLogin_FullMethodName  = "/user.User/ExternalLogin"
 )
 
 // UserClient is the client API for User service.
@@ -28,6 +35,25 @@ const (
 type UserClient interface {
 	// CheckUser returns whether the username and password are correct
 	CheckUser(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// Register creates a user. The username must not be taken.
+	Register(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// ChangePassword replaces the password of a user after checking the
+	// current one.
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*Result, error)
+	// DeleteUser removes a user after checking its password.
+	DeleteUser(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// Login checks the username and password and returns a signed session
+	// token for the user.
+	Login(ctx context.Context, in *Request, opts ...grpc.CallOption) (*LoginResult, error)
+	// GetKeys returns the public keys session tokens are signed with.
+	GetKeys(ctx context.Context, in *KeysRequest, opts ...grpc.CallOption) (*KeySet, error)
+	// SetRole changes the role of a user, and the hotels of a hotel manager.
+	// Tokens issued before keep the old role until they expire.
+	SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*Result, error)
+	// ExternalLogin returns a session token for a user logged in with the
+	// configured OpenID Connect issuer, creating the user on the first login.
+	// The ID token is verified against the keys of the issuer.
+	ExternalLogin(ctx context.Context, in *ExternalLoginRequest, opts ...grpc.CallOption) (*LoginResult, error)
 }
 
 type userClient struct {
@@ -47,12 +73,94 @@ func (c *userClient) CheckUser(ctx context.Context, in *Request, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *userClient) Register(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, User_Register_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, User_ChangePassword_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) DeleteUser(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, User_DeleteUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Login(ctx context.Context, in *Request, opts ...grpc.CallOption) (*LoginResult, error) {
+	out := new(LoginResult)
+	err := c.cc.Invoke(ctx, User_Login_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) GetKeys(ctx context.Context, in *KeysRequest, opts ...grpc.CallOption) (*KeySet, error) {
+	out := new(KeySet)
+	err := c.cc.Invoke(ctx, User_GetKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, User_SetRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ExternalLogin(ctx context.Context, in *ExternalLoginRequest, opts ...grpc.CallOption) (*LoginResult, error) {
+	out := new(LoginResult)
+	err := c.cc.Invoke(ctx, User_ExternalLogin_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServer is the server API for User service.
 // All implementations must embed UnimplementedUserServer
 // for forward compatibility
 type UserServer interface {
 	// CheckUser returns whether the username and password are correct
 	CheckUser(context.Context, *Request) (*Result, error)
+	// Register creates a user. The username must not be taken.
+	Register(context.Context, *Request) (*Result, error)
+	// ChangePassword replaces the password of a user after checking the
+	// current one.
+	ChangePassword(context.Context, *ChangePasswordRequest) (*Result, error)
+	// DeleteUser removes a user after checking its password.
+	DeleteUser(context.Context, *Request) (*Result, error)
+	// Login checks the username and password and returns a signed session
+	// token for the user.
+	Login(context.Context, *Request) (*LoginResult, error)
+	// GetKeys returns the public keys session tokens are signed with.
+	GetKeys(context.Context, *KeysRequest) (*KeySet, error)
+	// SetRole changes the role of a user, and the hotels of a hotel manager.
+	// Tokens issued before keep the old role until they expire.
+	SetRole(context.Context, *SetRoleRequest) (*Result, error)
+	// ExternalLogin returns a session token for a user logged in with the
+	// configured OpenID Connect issuer, creating the user on the first login.
+	// The ID token is verified against the keys of the issuer.
+	ExternalLogin(context.Context, *ExternalLoginRequest) (*LoginResult, error)
 	mustEmbedUnimplementedUserServer()
 }
 
@@ -63,6 +171,27 @@ type UnimplementedUserServer struct {
 func (UnimplementedUserServer) CheckUser(context.Context, *Request) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckUser not implemented")
 }
+func (UnimplementedUserServer) Register(context.Context, *Request) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedUserServer) ChangePassword(context.Context, *ChangePasswordRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (UnimplementedUserServer) DeleteUser(context.Context, *Request) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedUserServer) Login(context.Context, *Request) (*LoginResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedUserServer) GetKeys(context.Context, *KeysRequest) (*KeySet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeys not implemented")
+}
+func (UnimplementedUserServer) SetRole(context.Context, *SetRoleRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRole not implemented")
+}
+func (UnimplementedUserServer) ExternalLogin(context.Context, *ExternalLoginRequest) (*LoginResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExternalLogin not implemented")
+}
 func (UnimplementedUserServer) mustEmbedUnimplementedUserServer() {}
 
 // UnsafeUserServer may be embedded to opt out of forward compatibility for this service.
@@ -94,6 +223,132 @@ func _User_CheckUser_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _User_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Register(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_ChangePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).DeleteUser(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Login(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_GetKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).GetKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_GetKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).GetKeys(ctx, req.(*KeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_SetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).SetRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_SetRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).SetRole(ctx, req.(*SetRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_ExternalLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).ExternalLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_ExternalLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).ExternalLogin(ctx, req.(*ExternalLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // User_ServiceDesc is the grpc.ServiceDesc for User service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -105,6 +360,34 @@ var User_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckUser",
 			Handler:    _User_CheckUser_Handler,
 		},
+		{
+			MethodName: "Register",
+			Handler:    _User_Register_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _User_ChangePassword_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _User_DeleteUser_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _User_Login_Handler,
+		},
+		{
+			MethodName: "GetKeys",
+			Handler:    _User_GetKeys_Handler,
+		},
+		{
+			MethodName: "SetRole",
+			Handler:    _User_SetRole_Handler,
+		},
+		{
+			MethodName: "ExternalLogin",
+			Handler:    _User_ExternalLogin_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services/user/proto/user.proto",
@@ -1,11 +1,12 @@
 package user
 
 import (
-	"crypto/sha256"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
+	"github.com/bradfitz/gomemcache/memcache"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,6 +17,8 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
+	"hotelReservation/auth"
+	"hotelReservation/auth/oidc"
 	"hotelReservation/registry"
 	pb "hotelReservation/services/user/proto"
 	"hotelReservation/tls"
@@ -27,15 +30,43 @@ const name = "srv-user"
 type Server struct {
 	pb.UnimplementedUserServer
 
-	users map[string]string
+	mu    sync.RWMutex
+	users map[string]User
 	uuid  string
 
+	keyMu sync.RWMutex
+	keys  []signingKey
+
+	oidcProvider oidc.LazyProvider
+
 	Tracer         trace.Tracer
 	TracerProvider trace.TracerProvider
 	Registry       *registry.Client
 	Port           int
 	IpAddr         string
 	MongoClient    *mongo.Client
+	// MemcClient holds the failed login counters. Logins are not throttled
+	// without it.
+	MemcClient *memcache.Client
+	// PollInterval is how often the users are reloaded from mongo when
+	// change streams are unavailable.
+	PollInterval time.Duration
+	// OIDC is the OpenID Connect issuer and client whose ID tokens
+	// ExternalLogin accepts. External logins are disabled without an issuer.
+	OIDC oidc.Config
+	// TokenTTL is how long session tokens are valid.
+	TokenTTL time.Duration
+	// KeyRotation is how often a new token signing key is created.
+	KeyRotation time.Duration
+	// MaxLoginFailures and MaxIPLoginFailures are the failed logins of a
+	// user and of a client address after which they are locked out for
+	// LockoutDuration.
+	MaxLoginFailures   int
+	MaxIPLoginFailures int
+	LockoutDuration    time.Duration
+
+	// Policy restricts the RPCs users may call.
+	Policy *auth.Policy
 }
 
 // Run starts the server
@@ -45,8 +76,19 @@ func (s *Server) Run() error {
 	}
 
 	if s.users == nil {
-		s.users = loadUsers(s.MongoClient)
+		users, err := loadUsers(s.MongoClient)
+		if err != nil {
+			log.Error().Msgf("Failed get users data: %v", err)
+			users = make(map[string]User)
+		}
+		s.users = users
+	}
+	go s.watchUsers(context.Background())
+
+	if err := s.rotateKeys(context.Background()); err != nil {
+		log.Error().Msgf("Failed to load signing keys: %v", err)
 	}
+	go s.rotateKeysLoop(context.Background())
 
 	s.uuid = uuid.New().String()
 
@@ -58,6 +100,7 @@ func (s *Server) Run() error {
 				otelgrpc.WithTracerProvider(s.TracerProvider),
 				otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
 			),
+			s.Policy.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			otelgrpc.StreamServerInterceptor(
@@ -117,44 +160,49 @@ func (s *Server) CheckUser(ctx context.Context, req *pb.Request) (*pb.Result, er
 
 	log.Trace().Msg("CheckUser")
 
-	sum := sha256.Sum256([]byte(req.Password))
-	pass := fmt.Sprintf("%x", sum)
-
-	res.Correct = false
-	if true_pass, found := s.users[req.Username]; found {
-		res.Correct = pass == true_pass
+	_, correct, err := s.checkLogin(ctx, req.Username, req.Password)
+	if err != nil {
+		return nil, err
 	}
+	res.Correct = correct
 
-	log.Trace().Msgf("CheckUser %d", res.Correct)
+	log.Trace().Msgf("CheckUser %v", res.Correct)
 
 	return res, nil
 }
 
 // loadUsers loads hotel users from mongodb.
-func loadUsers(client *mongo.Client) map[string]string {
+func loadUsers(client *mongo.Client) (map[string]User, error) {
 	collection := client.Database("user-db").Collection("user")
 	curr, err := collection.Find(context.TODO(), bson.D{})
 	if err != nil {
-		log.Error().Msgf("Failed get users data: ", err)
+		return nil, err
 	}
 
 	var users []User
-	curr.All(context.TODO(), &users)
-	if err != nil {
-		log.Error().Msgf("Failed get users data: ", err)
+	if err := curr.All(context.TODO(), &users); err != nil {
+		return nil, err
 	}
 
-	res := make(map[string]string)
+	res := make(map[string]User)
 	for _, user := range users {
-		res[user.Username] = user.Password
+		res[user.Username] = user
 	}
 
 	log.Trace().Msg("Done load users")
 
-	return res
+	return res, nil
 }
 
 type User struct {
 	Username string `bson:"username"`
 	Password string `bson:"password"`
+	// Role is one of the auth roles, guest when unset.
+	Role string `bson:"role,omitempty"`
+	// Hotels are the hotels a hotel manager manages.
+	Hotels []string `bson:"hotels,omitempty"`
+	// Issuer and Subject identify users who log in with an OpenID Connect
+	// issuer. They have no password.
+	Issuer  string `bson:"issuer,omitempty"`
+	Subject string `bson:"subject,omitempty"`
 }
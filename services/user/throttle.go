@@ -0,0 +1,212 @@
+package user
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"hotelReservation/auth"
+)
+
+const (
+	defaultMaxLoginFailures   = 5
+	defaultMaxIPLoginFailures = 20
+	defaultLockoutDuration    = 15 * time.Minute
+	// loginBackoff is the wait after the first failed login. It doubles
+	// with every further failure until the lockout.
+	loginBackoff = time.Second
+)
+
+// login attempts are throttled by username and by client address, so
+// neither guessing the password of one user nor trying one password on
+// many users goes unchecked. The failure counters and the blocks live in
+// memcached, shared by all replicas:
+//
+//	login_fail_<kind>_<id>  failures since the first one, expiring after
+//	                        LockoutDuration
+//	login_wait_<kind>_<id>  end of the backoff after the last failure
+//	login_lock_<kind>_<id>  end of the lockout after too many failures
+type loginSubject struct {
+	kind        string
+	id          string
+	maxFailures int
+}
+
+// checkLogin checks a password like verify, unless the user or the client
+// is blocked after failed logins. A blocked login fails with
+// ResourceExhausted during a backoff and PermissionDenied during a lockout,
+// without checking the password.
+func (s *Server) checkLogin(ctx context.Context, username, password string) (User, bool, error) {
+	subjects := s.loginSubjects(ctx, username)
+	for _, sub := range subjects {
+		if err := s.loginBlocked(sub); err != nil {
+			return User{}, false, err
+		}
+	}
+
+	user, ok, err := s.verify(ctx, username, password)
+	if err != nil {
+		return User{}, false, status.Error(codes.Internal, err.Error())
+	}
+
+	if ok {
+		// the client keeps its count, it may be trying many users
+		s.resetLoginFailures(subjects[0])
+	} else {
+		for _, sub := range subjects {
+			s.loginFailed(sub)
+		}
+	}
+	return user, ok, nil
+}
+
+func (s *Server) loginSubjects(ctx context.Context, username string) []loginSubject {
+	subjects := []loginSubject{{"user", username, s.maxLoginFailures()}}
+	if ip := clientIP(ctx); ip != "" {
+		subjects = append(subjects, loginSubject{"ip", ip, s.maxIPLoginFailures()})
+	}
+	return subjects
+}
+
+// loginBlocked returns the error of a login by a blocked subject.
+// Memcached errors let the login through.
+func (s *Server) loginBlocked(sub loginSubject) error {
+	if s.MemcClient == nil {
+		return nil
+	}
+
+	items, err := s.MemcClient.GetMulti([]string{loginKey("lock", sub), loginKey("wait", sub)})
+	if err != nil {
+		log.Error().Msgf("Failed to get login blocks of %s: %v", sub.kind, err)
+		return nil
+	}
+	if item, ok := items[loginKey("lock", sub)]; ok {
+		return status.Errorf(codes.PermissionDenied, "too many failed logins, locked for %v", remaining(item))
+	}
+	if item, ok := items[loginKey("wait", sub)]; ok {
+		return status.Errorf(codes.ResourceExhausted, "failed login, retry in %v", remaining(item))
+	}
+	return nil
+}
+
+// loginFailed counts a failed login and blocks the subject for a backoff
+// or, after maxFailures, for the lockout.
+func (s *Server) loginFailed(sub loginSubject) {
+	if s.MemcClient == nil {
+		return
+	}
+
+	failures, err := s.countLoginFailure(sub)
+	if err != nil {
+		log.Error().Msgf("Failed to count failed login of %s: %v", sub.kind, err)
+		return
+	}
+
+	kind, wait := "wait", loginBackoff<<(failures-1)
+	if failures >= uint64(sub.maxFailures) || wait <= 0 || wait >= s.lockoutDuration() {
+		kind, wait = "lock", s.lockoutDuration()
+		log.Warn().Msgf("Locking out %s after %d failed logins", sub.kind, failures)
+	}
+	until := time.Now().Add(wait)
+	err = s.MemcClient.Set(&memcache.Item{
+		Key:        loginKey(kind, sub),
+		Value:      []byte(strconv.FormatInt(until.Unix(), 10)),
+		Expiration: expiration(wait),
+	})
+	if err != nil {
+		log.Error().Msgf("Failed to block %s: %v", sub.kind, err)
+	}
+}
+
+func (s *Server) countLoginFailure(sub loginSubject) (uint64, error) {
+	key := loginKey("fail", sub)
+	err := s.MemcClient.Add(&memcache.Item{
+		Key:        key,
+		Value:      []byte("0"),
+		Expiration: expiration(s.lockoutDuration()),
+	})
+	if err != nil && !errors.Is(err, memcache.ErrNotStored) {
+		return 0, err
+	}
+	return s.MemcClient.Increment(key, 1)
+}
+
+func (s *Server) resetLoginFailures(sub loginSubject) {
+	if s.MemcClient == nil {
+		return
+	}
+	for _, kind := range []string{"fail", "wait"} {
+		if err := s.MemcClient.Delete(loginKey(kind, sub)); err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+			log.Error().Msgf("Failed to reset failed logins of %s: %v", sub.kind, err)
+		}
+	}
+}
+
+// loginKey hashes the id, usernames and addresses may hold characters
+// memcached keys cannot.
+func loginKey(kind string, sub loginSubject) string {
+	sum := sha256.Sum256([]byte(sub.id))
+	return "login_" + kind + "_" + sub.kind + "_" + hex.EncodeToString(sum[:16])
+}
+
+// remaining returns the time left until the end of a block.
+func remaining(item *memcache.Item) time.Duration {
+	until, err := strconv.ParseInt(string(item.Value), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Until(time.Unix(until, 0)).Round(time.Second)
+}
+
+// expiration converts d to a memcached expiration, in whole seconds and at
+// least one.
+func expiration(d time.Duration) int32 {
+	if d < time.Second {
+		return 1
+	}
+	return int32((d + time.Second - 1) / time.Second)
+}
+
+// clientIP returns the address of the client logging in, as forwarded by
+// the frontend, or else the address of the caller.
+func clientIP(ctx context.Context) string {
+	if ip := auth.ClientIP(ctx); ip != "" {
+		return ip
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+	}
+	return ""
+}
+
+func (s *Server) maxLoginFailures() int {
+	if s.MaxLoginFailures <= 0 {
+		return defaultMaxLoginFailures
+	}
+	return s.MaxLoginFailures
+}
+
+func (s *Server) maxIPLoginFailures() int {
+	if s.MaxIPLoginFailures <= 0 {
+		return defaultMaxIPLoginFailures
+	}
+	return s.MaxIPLoginFailures
+}
+
+func (s *Server) lockoutDuration() time.Duration {
+	if s.LockoutDuration <= 0 {
+		return defaultLockoutDuration
+	}
+	return s.LockoutDuration
+}
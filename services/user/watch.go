@@ -0,0 +1,44 @@
+package user
+
+import (
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/net/context"
+	"hotelReservation/mongowatch"
+)
+
+// watchUsers keeps the users map in sync with changes made through other
+// replicas.
+func (s *Server) watchUsers(ctx context.Context) {
+	w := &mongowatch.Watcher{
+		Collection:   s.MongoClient.Database("user-db").Collection("user"),
+		PollInterval: s.PollInterval,
+		Apply:        s.applyChange,
+		Reload:       s.reloadUsers,
+	}
+	w.Run(ctx)
+}
+
+// applyChange stores an inserted or updated user in the users map.
+func (s *Server) applyChange(_ context.Context, doc bson.Raw) error {
+	var user User
+	if err := bson.Unmarshal(doc, &user); err != nil {
+		return err
+	}
+	log.Trace().Msgf("user change, username = %s", user.Username)
+	s.setUser(user)
+	return nil
+}
+
+// reloadUsers replaces the users map with the users in mongo.
+func (s *Server) reloadUsers(_ context.Context) error {
+	users, err := loadUsers(s.MongoClient)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.users = users
+	s.mu.Unlock()
+	return nil
+}
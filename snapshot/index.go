@@ -0,0 +1,82 @@
+package snapshot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hailocab/go-geoindex"
+)
+
+// Index is a geoindex.ClusteringIndex that also keeps its points, so that
+// it can be written to a snapshot.
+type Index struct {
+	*geoindex.ClusteringIndex
+
+	points map[string]geoindex.Point
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{
+		ClusteringIndex: geoindex.NewClusteringIndex(),
+		points:          make(map[string]geoindex.Point),
+	}
+}
+
+// Load returns an index holding the points of the snapshot at path. See
+// Read for the errors returned.
+func Load(path string, maxAge time.Duration) (*Index, error) {
+	points, err := Read(path, maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	index := NewIndex()
+	for i := range points {
+		index.Add(&points[i])
+	}
+
+	return index, nil
+}
+
+// Add adds a point, replacing any point with the same id.
+func (index *Index) Add(point geoindex.Point) {
+	index.ClusteringIndex.Add(point)
+	index.points[point.Id()] = point
+}
+
+// Remove removes a point.
+func (index *Index) Remove(id string) {
+	index.ClusteringIndex.Remove(id)
+	delete(index.points, id)
+}
+
+// Len returns the number of points in the index.
+func (index *Index) Len() int {
+	return len(index.points)
+}
+
+// Check returns an ErrStale error unless the index holds exactly the points
+// with ids, like the distinct ids of the collection it is built from. It is
+// meant for loaded snapshots; points moved since the snapshot are not noticed.
+func (index *Index) Check(ids []string) error {
+	if len(ids) != len(index.points) {
+		return fmt.Errorf("%w: %d points, want %d", ErrStale, len(index.points), len(ids))
+	}
+	for _, id := range ids {
+		if _, ok := index.points[id]; !ok {
+			return fmt.Errorf("%w: point %s missing", ErrStale, id)
+		}
+	}
+	return nil
+}
+
+// Save writes the points of the index to a snapshot at path.
+func (index *Index) Save(path string) error {
+	points := make([]Point, 0, len(index.points))
+	for _, p := range index.points {
+		points = append(points, Point{Pid: p.Id(), Plat: p.Lat(), Plon: p.Lon()})
+	}
+
+	return Write(path, points)
+}
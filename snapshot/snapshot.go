@@ -0,0 +1,135 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Version is the current snapshot format version. Snapshots written with
+// another version are treated as stale.
+const Version = 1
+
+var magic = [4]byte{'H', 'R', 'G', 'I'}
+
+var (
+	// ErrMissing is returned by Read when there is no snapshot file.
+	ErrMissing = errors.New("snapshot: missing")
+	// ErrStale is returned by Read when the snapshot is too old or was
+	// written with another format version.
+	ErrStale = errors.New("snapshot: stale")
+)
+
+// Point is a location stored in a snapshot. It implements geoindex.Point.
+type Point struct {
+	Pid  string
+	Plat float64
+	Plon float64
+}
+
+func (p *Point) Lat() float64 { return p.Plat }
+func (p *Point) Lon() float64 { return p.Plon }
+func (p *Point) Id() string   { return p.Pid }
+
+// Write stores points in a snapshot file at path. The file is replaced
+// atomically, so a concurrent reader sees either the old or the new one.
+//
+// The format is the magic "HRGI", then the version (uint16), the creation
+// time (int64 unix nanoseconds) and the number of points (uint32), then per
+// point the id length (uvarint), the id and lat/lon as float64 bits, and a
+// CRC-32 of everything before it. Integers are little endian.
+func Write(path string, points []Point) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	crc := crc32.NewIEEE()
+	w := bufio.NewWriter(io.MultiWriter(tmp, crc))
+
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(magic[:])
+	binary.Write(w, binary.LittleEndian, uint16(Version))
+	binary.Write(w, binary.LittleEndian, time.Now().UnixNano())
+	binary.Write(w, binary.LittleEndian, uint32(len(points)))
+	for _, p := range points {
+		n := binary.PutUvarint(buf[:], uint64(len(p.Pid)))
+		w.Write(buf[:n])
+		w.WriteString(p.Pid)
+		binary.Write(w, binary.LittleEndian, math.Float64bits(p.Plat))
+		binary.Write(w, binary.LittleEndian, math.Float64bits(p.Plon))
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := binary.Write(tmp, binary.LittleEndian, crc.Sum32()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Read loads the points stored at path. It returns ErrMissing when there is
+// no snapshot, and ErrStale when the snapshot has another version or, for a
+// positive maxAge, was written more than maxAge ago.
+func Read(path string, maxAge time.Duration) ([]Point, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrMissing
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 4+2+8+4+4 {
+		return nil, fmt.Errorf("snapshot: %s truncated", path)
+	}
+	body, sum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, fmt.Errorf("snapshot: %s checksum mismatch", path)
+	}
+	if [4]byte(body[:4]) != magic {
+		return nil, fmt.Errorf("snapshot: %s is not a snapshot", path)
+	}
+	if v := binary.LittleEndian.Uint16(body[4:]); v != Version {
+		return nil, fmt.Errorf("%w: %s has version %d, want %d", ErrStale, path, v, Version)
+	}
+	created := time.Unix(0, int64(binary.LittleEndian.Uint64(body[6:])))
+	if maxAge > 0 && time.Since(created) > maxAge {
+		return nil, fmt.Errorf("%w: %s written at %v", ErrStale, path, created)
+	}
+
+	count := binary.LittleEndian.Uint32(body[14:])
+	body = body[18:]
+	points := make([]Point, 0, count)
+	for i := uint32(0); i < count; i++ {
+		n, k := binary.Uvarint(body)
+		if k <= 0 || uint64(len(body)-k) < n+16 {
+			return nil, fmt.Errorf("snapshot: %s truncated", path)
+		}
+		body = body[k:]
+		p := Point{Pid: string(body[:n])}
+		body = body[n:]
+		p.Plat = math.Float64frombits(binary.LittleEndian.Uint64(body))
+		p.Plon = math.Float64frombits(binary.LittleEndian.Uint64(body[8:]))
+		body = body[16:]
+		points = append(points, p)
+	}
+
+	return points, nil
+}
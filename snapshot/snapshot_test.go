@@ -0,0 +1,160 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// benchPoints is the number of generated points of the benchmarks.
+const benchPoints = 100000
+
+type hotel struct {
+	Pid  string  `bson:"hotelId"`
+	Plat float64 `bson:"lat"`
+	Plon float64 `bson:"lon"`
+}
+
+func (h *hotel) Lat() float64 { return h.Plat }
+func (h *hotel) Lon() float64 { return h.Plon }
+func (h *hotel) Id() string   { return h.Pid }
+
+// benchHotels returns points around San Francisco, like the seeded hotels.
+func benchHotels() []*hotel {
+	r := rand.New(rand.NewSource(1))
+	hotels := make([]*hotel, benchPoints)
+	for i := range hotels {
+		hotels[i] = &hotel{strconv.Itoa(i), 37.7 + r.Float64()*0.3, -122.5 + r.Float64()*0.3}
+	}
+	return hotels
+}
+
+func TestSaveLoad(t *testing.T) {
+	index := NewIndex()
+	index.Add(&hotel{"1", 37.7867, -122.4112})
+	index.Add(&hotel{"2", 37.7854, -122.4005})
+	path := filepath.Join(t.TempDir(), "geo.snap")
+	if err := index.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != 2 {
+		t.Fatalf("loaded %d points, want 2", loaded.Len())
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.snap"), 0); err != ErrMissing {
+		t.Errorf("Load of a missing snapshot = %v, want ErrMissing", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	index := NewIndex()
+	index.Add(&hotel{"1", 37.7867, -122.4112})
+	index.Add(&hotel{"2", 37.7854, -122.4005})
+
+	tests := []struct {
+		ids   []string
+		stale bool
+	}{
+		{[]string{"2", "1"}, false},
+		{[]string{"1"}, true},
+		{[]string{"1", "2", "3"}, true},
+		{[]string{"1", "3"}, true},
+	}
+	for _, tt := range tests {
+		err := index.Check(tt.ids)
+		if stale := errors.Is(err, ErrStale); stale != tt.stale || (err != nil && !stale) {
+			t.Errorf("Check(%v) = %v, want stale %v", tt.ids, err, tt.stale)
+		}
+	}
+}
+
+// BenchmarkLoad measures the startup path of the geo and attractions
+// services with a snapshot: reading it and building the index.
+func BenchmarkLoad(b *testing.B) {
+	index := NewIndex()
+	for _, h := range benchHotels() {
+		index.Add(h)
+	}
+	path := filepath.Join(b.TempDir(), "geo.snap")
+	if err := index.Save(path); err != nil {
+		b.Fatal(err)
+	}
+	if fi, err := os.Stat(path); err == nil {
+		b.SetBytes(fi.Size())
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Load(path, time.Hour); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBuildFromBSON measures the startup path without a snapshot,
+// short of the MongoDB round trips: decoding the documents and building the
+// index.
+func BenchmarkBuildFromBSON(b *testing.B) {
+	hotels := benchHotels()
+	docs := make([][]byte, len(hotels))
+	for i, h := range hotels {
+		docs[i], _ = bson.Marshal(h)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index := NewIndex()
+		for _, doc := range docs {
+			h := new(hotel)
+			if err := bson.Unmarshal(doc, h); err != nil {
+				b.Fatal(err)
+			}
+			index.Add(h)
+		}
+	}
+}
+
+// BenchmarkLoadMongo measures the startup path without a snapshot against
+// the geo-db.geo collection at SNAPSHOT_BENCH_MONGO, like localhost:27017.
+func BenchmarkLoadMongo(b *testing.B) {
+	addr := os.Getenv("SNAPSHOT_BENCH_MONGO")
+	if addr == "" {
+		b.Skip("SNAPSHOT_BENCH_MONGO not set")
+	}
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://"+addr))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+	collection := client.Database("geo-db").Collection("geo")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		curr, err := collection.Find(context.Background(), bson.D{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		var hotels []*hotel
+		if err := curr.All(context.Background(), &hotels); err != nil {
+			b.Fatal(err)
+		}
+		index := NewIndex()
+		for _, h := range hotels {
+			index.Add(h)
+		}
+	}
+}
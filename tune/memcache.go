@@ -0,0 +1,48 @@
+package tune
+
+import (
+	"sync"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// setMultiWorkers bounds the connections a SetMulti call uses at once.
+const setMultiWorkers = 8
+
+// SetMulti stores items in memcached, the counterpart of GetMulti. The
+// client has no multi-key set command, so the items are written over a few
+// concurrent connections. It returns the first error, after all items have
+// been tried.
+func SetMulti(client *memcache.Client, items []*memcache.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	next := make(chan *memcache.Item)
+	workers := setMultiWorkers
+	if len(items) < workers {
+		workers = len(items)
+	}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for item := range next {
+				if err := client.Set(item); err != nil {
+					once.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+	for _, item := range items {
+		next <- item
+	}
+	close(next)
+	wg.Wait()
+	return firstErr
+}
@@ -4,6 +4,9 @@ math.random(); math.random(); math.random()
 
 local url = "http://localhost:5000"
 
+-- session token of the last successful login, sent with reservations
+local token = nil
+
 local function get_user()
   local id = math.random(0, 500)
   local user_name = "Cornell_" .. tostring(id)
@@ -67,46 +70,46 @@ local function recommend()
 end
 
 local function reserve()
-  local in_date = math.random(9, 23)
-  local out_date = in_date + math.random(1, 5)
+  -- reservations cannot start in the past
+  local in_date = os.time() + math.random(1, 15) * 86400
+  local out_date = in_date + math.random(1, 5) * 86400
 
-  local in_date_str = tostring(in_date)
-  if in_date <= 9 then
-    in_date_str = "2015-04-0" .. in_date_str 
-  else
-    in_date_str = "2015-04-" .. in_date_str
-  end
-
-  local out_date_str = tostring(out_date)
-  if out_date <= 9 then
-    out_date_str = "2015-04-0" .. out_date_str 
-  else
-    out_date_str = "2015-04-" .. out_date_str
-  end
+  local in_date_str = os.date("!%Y-%m-%d", in_date)
+  local out_date_str = os.date("!%Y-%m-%d", out_date)
 
   local hotel_id = tostring(math.random(1, 80))
-  local user_id, password = get_user()
-  local cust_name = user_id
+  local cust_name = get_user()
 
   local num_room = "1"
 
   local method = "POST"
   local path = url .. "/reservation?inDate=" .. in_date_str .. 
     "&outDate=" .. out_date_str .. "&lat=" .. tostring(lat) .. "&lon=" .. tostring(lon) ..
-    "&hotelId=" .. hotel_id .. "&customerName=" .. cust_name .. "&username=" .. user_id ..
-    "&password=" .. password .. "&number=" .. num_room
+    "&hotelId=" .. hotel_id .. "&customerName=" .. cust_name .. "&number=" .. num_room
   local headers = {}
-  -- headers["Content-Type"] = "application/x-www-form-urlencoded"
+  if token then
+    headers["Authorization"] = "Bearer " .. token
+  end
   return wrk.format(method, path, headers, nil)
 end
 
 local function user_login()
   local user_name, password = get_user()
   local method = "POST"
-  local path = url .. "/user?username=" .. user_name .. "&password=" .. password
+  local path = url .. "/login"
   local headers = {}
-  -- headers["Content-Type"] = "application/x-www-form-urlencoded"
-  return wrk.format(method, path, headers, nil)
+  headers["Content-Type"] = "application/x-www-form-urlencoded"
+  local body = "username=" .. user_name .. "&password=" .. password
+  return wrk.format(method, path, headers, body)
+end
+
+response = function(status, headers, body)
+  if status == 200 then
+    local t = body:match('"token":"([^"]+)"')
+    if t then
+      token = t
+    end
+  end
 end
 
 request = function()